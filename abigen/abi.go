@@ -0,0 +1,105 @@
+// Package abigen generates typed Go clients from the ABI files emitted by contracts
+// built with near-sdk-rs.
+package abigen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// Function kinds.
+const (
+	// KindView is the kind of view functions, called without a transaction.
+	KindView = "view"
+	// KindCall is the kind of change functions, called with a signed transaction.
+	KindCall = "call"
+)
+
+// Function modifiers.
+const (
+	// ModifierInit marks functions initializing the contract state.
+	ModifierInit = "init"
+	// ModifierPayable marks functions accepting a deposit.
+	ModifierPayable = "payable"
+	// ModifierPrivate marks functions that can only be called by the contract itself.
+	ModifierPrivate = "private"
+)
+
+// SerializationJSON is the JSON serialization type of args and results.
+const SerializationJSON = "json"
+
+// ABI describes the functions of a contract, and the JSON schemas of their args and results.
+type ABI struct {
+	SchemaVersion string   `json:"schema_version"`
+	Metadata      Metadata `json:"metadata"`
+	Body          Body     `json:"body"`
+}
+
+// Metadata describes the contract.
+type Metadata struct {
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// Body holds the functions of the contract and the root schema with their shared definitions.
+type Body struct {
+	Functions  []Function `json:"functions"`
+	RootSchema Schema     `json:"root_schema"`
+}
+
+// Function describes a contract function.
+type Function struct {
+	Name      string   `json:"name"`
+	Doc       string   `json:"doc,omitempty"`
+	Kind      string   `json:"kind"`
+	Modifiers []string `json:"modifiers,omitempty"`
+	Params    *Params  `json:"params,omitempty"`
+	Result    *Type    `json:"result,omitempty"`
+}
+
+// HasModifier reports whether the function has the modifier.
+func (f Function) HasModifier(modifier string) bool {
+	for _, m := range f.Modifiers {
+		if m == modifier {
+			return true
+		}
+	}
+	return false
+}
+
+// Params describes the args of a function.
+type Params struct {
+	SerializationType string `json:"serialization_type"`
+	Args              []Arg  `json:"args"`
+}
+
+// Arg describes a named arg of a function.
+type Arg struct {
+	Name       string `json:"name"`
+	TypeSchema Schema `json:"type_schema"`
+}
+
+// Type describes the result of a function.
+type Type struct {
+	SerializationType string `json:"serialization_type"`
+	TypeSchema        Schema `json:"type_schema"`
+}
+
+// Parse decodes an ABI from its JSON encoding.
+func Parse(data []byte) (*ABI, error) {
+	var abi ABI
+	if err := json.Unmarshal(data, &abi); err != nil {
+		return nil, fmt.Errorf("unmarshaling abi: %v", err)
+	}
+	return &abi, nil
+}
+
+// Load reads an ABI from a file.
+func Load(path string) (*ABI, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading abi file: %v", err)
+	}
+	return Parse(data)
+}
@@ -0,0 +1,539 @@
+package abigen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Option controls the generated code.
+type Option func(*generator)
+
+// WithTypeName sets the name of the generated client type. It defaults to the contract
+// name of the ABI metadata in CamelCase, or Contract if the ABI has no name.
+func WithTypeName(name string) Option {
+	return func(g *generator) {
+		g.typeName = name
+	}
+}
+
+// Generate returns the formatted source of a Go file in package pkg with a typed client
+// for the functions of the ABI, and types for the definitions of its root schema.
+//
+// View functions are called with contract.View and change functions with contract.Call.
+// Private functions are skipped, since only the contract can call them.
+func Generate(abi *ABI, pkg string, opts ...Option) ([]byte, error) {
+	if !token.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("invalid package name %q", pkg)
+	}
+	g := &generator{
+		abi:     abi,
+		pkg:     pkg,
+		imports: make(map[string]bool),
+	}
+	if abi.Metadata.Name != "" {
+		g.typeName = exportedName(abi.Metadata.Name)
+	}
+	if g.typeName == "" {
+		g.typeName = "Contract"
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if !token.IsIdentifier(g.typeName) || !token.IsExported(g.typeName) {
+		return nil, fmt.Errorf("invalid type name %q", g.typeName)
+	}
+	if err := g.generate(); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(g.source())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v", err)
+	}
+	return src, nil
+}
+
+type generator struct {
+	abi      *ABI
+	pkg      string
+	typeName string
+	imports  map[string]bool
+	body     bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.body, format, args...)
+}
+
+func (g *generator) source() []byte {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by near-abigen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", g.pkg)
+	var std, other []string
+	for path := range g.imports {
+		if strings.Contains(path, ".") {
+			other = append(other, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+	b.WriteString("import (\n")
+	for _, path := range std {
+		fmt.Fprintf(&b, "%q\n", path)
+	}
+	b.WriteString("\n")
+	for _, path := range other {
+		if path == "github.com/textileio/near-api-go" {
+			fmt.Fprintf(&b, "api %q\n", path)
+		} else {
+			fmt.Fprintf(&b, "%q\n", path)
+		}
+	}
+	b.WriteString(")\n")
+	b.Write(g.body.Bytes())
+	return b.Bytes()
+}
+
+func (g *generator) generate() error {
+	names := make([]string, 0, len(g.abi.Body.RootSchema.Definitions))
+	for name := range g.abi.Body.RootSchema.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := g.definition(name, g.abi.Body.RootSchema.Definitions[name]); err != nil {
+			return fmt.Errorf("generating definition %s: %v", name, err)
+		}
+	}
+
+	g.imports["github.com/textileio/near-api-go"] = true
+	g.imports["github.com/textileio/near-api-go/account"] = true
+	g.imports["github.com/textileio/near-api-go/contract"] = true
+	if g.abi.Metadata.Name != "" {
+		g.printf("\n// %s is a client of the %s contract.\n", g.typeName, g.abi.Metadata.Name)
+	} else {
+		g.printf("\n// %s is a client of the contract.\n", g.typeName)
+	}
+	g.printf("type %s struct {\n*contract.Contract\n}\n", g.typeName)
+	g.printf("\n// New%s creates a %s for the contract at contractID. View functions are called with the viewer,\n",
+		g.typeName, g.typeName)
+	g.printf("// and change functions are signed by the sender, which may be nil if only view functions are used.\n")
+	g.printf("func New%s(contractID string, viewer api.Viewer, sender account.Sender) *%s {\n", g.typeName, g.typeName)
+	g.printf("return &%s{Contract: contract.New(contractID, viewer, sender)}\n}\n", g.typeName)
+
+	for _, f := range g.abi.Body.Functions {
+		if f.HasModifier(ModifierPrivate) {
+			continue
+		}
+		if err := g.function(f); err != nil {
+			return fmt.Errorf("generating function %s: %v", f.Name, err)
+		}
+	}
+	return nil
+}
+
+func (g *generator) definition(name string, s *Schema) error {
+	typeName := exportedName(name)
+	g.printf("\n")
+	g.comment(typeName+" is the "+name+" type of the contract.", s.Description)
+	if values, ok := stringEnum(s); ok {
+		g.printf("type %s string\n\n", typeName)
+		g.printf("// Values of %s.\nconst (\n", typeName)
+		for _, v := range values {
+			g.printf("%s%s %s = %q\n", typeName, exportedName(v), typeName, v)
+		}
+		g.printf(")\n")
+		return nil
+	}
+	if len(s.Properties) > 0 && s.Ref == "" {
+		st, err := g.structType(s)
+		if err != nil {
+			return err
+		}
+		g.printf("type %s %s\n", typeName, st)
+		return nil
+	}
+	t, err := g.goType(s)
+	if err != nil {
+		return err
+	}
+	g.printf("type %s = %s\n", typeName, t)
+	return nil
+}
+
+func (g *generator) function(f Function) error {
+	if f.Kind != KindView && f.Kind != KindCall {
+		return fmt.Errorf("unknown function kind %q", f.Kind)
+	}
+	name := exportedName(f.Name)
+	var params, args []string
+	params = append(params, "ctx context.Context")
+	g.imports["context"] = true
+	if f.Params != nil && len(f.Params.Args) > 0 {
+		if f.Params.SerializationType != SerializationJSON {
+			return fmt.Errorf("unsupported %s serialization of args", f.Params.SerializationType)
+		}
+		for _, arg := range f.Params.Args {
+			t, err := g.goType(&arg.TypeSchema)
+			if err != nil {
+				return fmt.Errorf("arg %s: %v", arg.Name, err)
+			}
+			param := paramName(arg.Name)
+			params = append(params, param+" "+t)
+			args = append(args, fmt.Sprintf("%q: %s,", arg.Name, param))
+		}
+	}
+	resultType := ""
+	if f.Result != nil {
+		if f.Result.SerializationType != SerializationJSON {
+			return fmt.Errorf("unsupported %s serialization of result", f.Result.SerializationType)
+		}
+		t, err := g.goType(&f.Result.TypeSchema)
+		if err != nil {
+			return fmt.Errorf("result: %v", err)
+		}
+		resultType = t
+	}
+	if f.Kind == KindView {
+		params = append(params, "opts ...api.CallFunctionOption")
+	} else {
+		params = append(params, "gas uint64")
+		if f.HasModifier(ModifierPayable) {
+			g.imports["math/big"] = true
+			params = append(params, "deposit *big.Int")
+		}
+	}
+
+	g.printf("\n")
+	summary := name + " calls the view function " + f.Name + "."
+	if f.Kind == KindCall {
+		summary = name + " calls the change function " + f.Name + ". The default gas is used if gas is zero."
+	}
+	g.comment(summary, f.Doc)
+	if resultType != "" {
+		g.printf("func (c *%s) %s(%s) (%s, error) {\n", g.typeName, name, strings.Join(params, ", "), resultType)
+	} else {
+		g.printf("func (c *%s) %s(%s) error {\n", g.typeName, name, strings.Join(params, ", "))
+	}
+	argsExpr := "nil"
+	if len(args) > 0 {
+		g.printf("args := map[string]interface{}{\n%s\n}\n", strings.Join(args, "\n"))
+		argsExpr = "args"
+	}
+	typeArg := resultType
+	if typeArg == "" {
+		g.imports["encoding/json"] = true
+		typeArg = "json.RawMessage"
+	}
+	var call string
+	if f.Kind == KindView {
+		call = fmt.Sprintf("contract.View[%s](ctx, c.Contract, %q, %s, opts...)", typeArg, f.Name, argsExpr)
+	} else {
+		deposit := "nil"
+		if f.HasModifier(ModifierPayable) {
+			deposit = "deposit"
+		}
+		call = fmt.Sprintf("contract.Call[%s](ctx, c.Contract, %q, %s, gas, %s)", typeArg, f.Name, argsExpr, deposit)
+	}
+	if resultType != "" {
+		g.printf("return %s\n}\n", call)
+	} else {
+		g.printf("_, err := %s\nreturn err\n}\n", call)
+	}
+	return nil
+}
+
+// goType returns the Go type of values matching the schema.
+func (g *generator) goType(s *Schema) (string, error) {
+	if s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/definitions/")
+		if name == s.Ref {
+			return "", fmt.Errorf("unsupported reference %s", s.Ref)
+		}
+		if _, ok := g.abi.Body.RootSchema.Definitions[name]; !ok {
+			return "", fmt.Errorf("unknown definition %s", name)
+		}
+		return exportedName(name), nil
+	}
+	if len(s.AllOf) == 1 {
+		return g.goType(s.AllOf[0])
+	}
+	if inner, ok := optional(s); ok {
+		t, err := g.goType(inner)
+		if err != nil {
+			return "", err
+		}
+		return nullable(t), nil
+	}
+	if _, ok := stringEnum(s); ok {
+		return "string", nil
+	}
+
+	types := make([]string, 0, len(s.Type))
+	nullableType := false
+	for _, t := range s.Type {
+		if t == "null" {
+			nullableType = true
+		} else {
+			types = append(types, t)
+		}
+	}
+	if len(types) != 1 {
+		g.imports["encoding/json"] = true
+		return "json.RawMessage", nil
+	}
+	var t string
+	switch types[0] {
+	case "boolean":
+		t = "bool"
+	case "string":
+		t = "string"
+	case "number":
+		t = "float64"
+	case "integer":
+		it, err := integerType(s.Format)
+		if err != nil {
+			return "", err
+		}
+		if it == "json.Number" {
+			g.imports["encoding/json"] = true
+		}
+		t = it
+	case "array":
+		if s.Items == nil {
+			g.imports["encoding/json"] = true
+			t = "[]json.RawMessage"
+			break
+		}
+		item, err := g.goType(s.Items)
+		if err != nil {
+			return "", err
+		}
+		t = "[]" + item
+	case "object":
+		switch {
+		case len(s.Properties) > 0:
+			st, err := g.structType(s)
+			if err != nil {
+				return "", err
+			}
+			t = st
+		case s.AdditionalProperties != nil:
+			value, err := g.goType(s.AdditionalProperties)
+			if err != nil {
+				return "", err
+			}
+			t = "map[string]" + value
+		default:
+			g.imports["encoding/json"] = true
+			t = "map[string]json.RawMessage"
+		}
+	default:
+		return "", fmt.Errorf("unsupported type %s", types[0])
+	}
+	if nullableType {
+		t = nullable(t)
+	}
+	return t, nil
+}
+
+func (g *generator) structType(s *Schema) (string, error) {
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	for _, p := range s.Properties {
+		t, err := g.goType(p.Schema)
+		if err != nil {
+			return "", fmt.Errorf("property %s: %v", p.Name, err)
+		}
+		tag := p.Name
+		if !s.IsRequired(p.Name) {
+			t = nullable(t)
+			tag += ",omitempty"
+		}
+		if p.Schema.Description != "" {
+			for _, line := range strings.Split(strings.TrimSpace(p.Schema.Description), "\n") {
+				fmt.Fprintf(&b, "// %s\n", strings.TrimSpace(line))
+			}
+		}
+		fmt.Fprintf(&b, "%s %s `json:%q`\n", exportedName(p.Name), t, tag)
+	}
+	b.WriteString("}")
+	return b.String(), nil
+}
+
+// comment writes a doc comment with the summary line, followed by the doc of the ABI in
+// a separate paragraph.
+func (g *generator) comment(summary, doc string) {
+	g.printf("// %s\n", summary)
+	doc = strings.TrimSpace(doc)
+	if doc == "" {
+		return
+	}
+	g.printf("//\n")
+	for _, line := range strings.Split(doc, "\n") {
+		g.printf("// %s\n", strings.TrimSpace(line))
+	}
+}
+
+// optional returns the inner schema of schemas like {"anyOf": [<inner>, {"type": "null"}]}.
+func optional(s *Schema) (*Schema, bool) {
+	alts := s.AnyOf
+	if len(alts) == 0 {
+		alts = s.OneOf
+	}
+	if len(alts) != 2 {
+		return nil, false
+	}
+	isNull := func(s *Schema) bool {
+		return len(s.Type) == 1 && s.Type[0] == "null"
+	}
+	if isNull(alts[1]) {
+		return alts[0], true
+	}
+	if isNull(alts[0]) {
+		return alts[1], true
+	}
+	return nil, false
+}
+
+// stringEnum returns the values of schemas of unit enums, like {"type": "string", "enum": ["A", "B"]},
+// or the oneOf of such schemas generated for documented variants.
+func stringEnum(s *Schema) ([]string, bool) {
+	if len(s.OneOf) > 0 {
+		var values []string
+		for _, alt := range s.OneOf {
+			v, ok := stringEnum(alt)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, v...)
+		}
+		return values, true
+	}
+	if len(s.Enum) == 0 || len(s.Type) != 1 || s.Type[0] != "string" {
+		return nil, false
+	}
+	values := make([]string, len(s.Enum))
+	for i, raw := range s.Enum {
+		if err := json.Unmarshal(raw, &values[i]); err != nil {
+			return nil, false
+		}
+	}
+	return values, true
+}
+
+func integerType(format string) (string, error) {
+	switch format {
+	case "int8", "int16", "int32", "int64", "uint8", "uint16", "uint32", "uint64":
+		return format, nil
+	case "", "int":
+		return "int64", nil
+	case "uint":
+		return "uint64", nil
+	case "int128", "uint128":
+		// Go has no 128-bit integers, and big.Int values aren't decoded from JSON numbers
+		// without losing the ones that don't fit a float64.
+		return "json.Number", nil
+	default:
+		return "", fmt.Errorf("unsupported integer format %s", format)
+	}
+}
+
+// nullable returns the type of values of t that may be null.
+func nullable(t string) string {
+	if strings.HasPrefix(t, "*") || strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[") ||
+		t == "json.RawMessage" {
+		return t
+	}
+	return "*" + t
+}
+
+var initialisms = map[string]string{
+	"api":  "API",
+	"id":   "ID",
+	"ids":  "IDs",
+	"json": "JSON",
+	"url":  "URL",
+}
+
+// words splits names like "get_account_id", "AccountId" or "Pair" into words.
+func words(name string) []string {
+	var res []string
+	for _, field := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		res = append(res, camelWords(field)...)
+	}
+	return res
+}
+
+// camelWords splits CamelCase words like "AccountId" or "HTTPServer" at their upper case
+// letters, keeping runs of upper case letters like "HTTP" together.
+func camelWords(s string) []string {
+	runes := []rune(s)
+	var res []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		if !unicode.IsUpper(runes[i]) {
+			continue
+		}
+		prev := runes[i-1]
+		endOfRun := unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if unicode.IsLower(prev) || unicode.IsDigit(prev) || endOfRun {
+			res = append(res, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(res, string(runes[start:]))
+}
+
+// exportedWord capitalizes a word, or spells it as an initialism if it is one.
+func exportedWord(word string) string {
+	if s, ok := initialisms[strings.ToLower(word)]; ok {
+		return s
+	}
+	return strings.ToUpper(word[:1]) + word[1:]
+}
+
+// exportedName converts names like "get_account_id" or "AccountId" to exported Go names
+// like "GetAccountID" or "AccountID".
+func exportedName(name string) string {
+	var b strings.Builder
+	for _, word := range words(name) {
+		b.WriteString(exportedWord(word))
+	}
+	s := b.String()
+	if s != "" && unicode.IsDigit(rune(s[0])) {
+		s = "X" + s
+	}
+	return s
+}
+
+// paramName converts arg names like "account_id" to parameter names like "accountID".
+func paramName(name string) string {
+	ws := words(name)
+	if len(ws) == 0 {
+		return "arg"
+	}
+	var b strings.Builder
+	b.WriteString(strings.ToLower(ws[0]))
+	for _, word := range ws[1:] {
+		b.WriteString(exportedWord(word))
+	}
+	s := b.String()
+	switch s {
+	case "ctx", "c", "args", "opts", "gas", "deposit", "err", "contract", "api", "account", "json", "big":
+		return s + "Arg"
+	}
+	if token.IsKeyword(s) || unicode.IsDigit(rune(s[0])) {
+		return s + "Arg"
+	}
+	return s
+}
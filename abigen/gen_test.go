@@ -0,0 +1,103 @@
+package abigen
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	abi, err := Load("testdata/counter_abi.json")
+	require.NoError(t, err)
+	src, err := Generate(abi, "counter")
+	require.NoError(t, err)
+	golden, err := ioutil.ReadFile("internal/counter/counter.go")
+	require.NoError(t, err)
+	require.Equal(t, string(golden), string(src), "run go generate ./abigen/...")
+
+	src, err = Generate(abi, "counter", WithTypeName("Client"))
+	require.NoError(t, err)
+	require.Contains(t, string(src), "func NewClient(contractID string")
+	require.NotContains(t, string(src), "OnTransfer")
+
+	_, err = Generate(abi, "my-package")
+	require.Error(t, err)
+	_, err = Generate(abi, "counter", WithTypeName("client"))
+	require.Error(t, err)
+}
+
+func TestGenerateErrors(t *testing.T) {
+	abi, err := Parse([]byte(`{"body": {"functions": [{
+		"name": "set",
+		"kind": "call",
+		"params": {"serialization_type": "borsh", "args": [{"name": "v", "type_schema": {"type": "string"}}]}
+	}]}}`))
+	require.NoError(t, err)
+	_, err = Generate(abi, "example")
+	require.EqualError(t, err, "generating function set: unsupported borsh serialization of args")
+
+	abi, err = Parse([]byte(`{"body": {"functions": [{
+		"name": "get",
+		"kind": "view",
+		"result": {"serialization_type": "json", "type_schema": {"$ref": "#/definitions/Missing"}}
+	}]}}`))
+	require.NoError(t, err)
+	_, err = Generate(abi, "example")
+	require.EqualError(t, err, "generating function get: result: unknown definition Missing")
+}
+
+func TestSchema(t *testing.T) {
+	abi, err := Parse([]byte(`{"body": {"functions": [], "root_schema": {"definitions": {
+		"Point": {
+			"type": "object",
+			"properties": {"y": {"type": "integer"}, "x": {"type": "integer"}},
+			"additionalProperties": false
+		},
+		"Pair": {"type": "array", "items": [{"type": "string"}, true], "maxItems": 2, "minItems": 2}
+	}}}}`))
+	require.NoError(t, err)
+	point := abi.Body.RootSchema.Definitions["Point"]
+	require.Len(t, point.Properties, 2)
+	require.Equal(t, "y", point.Properties[0].Name)
+	require.Equal(t, "x", point.Properties[1].Name)
+	require.Nil(t, point.AdditionalProperties)
+	pair := abi.Body.RootSchema.Definitions["Pair"]
+	require.Nil(t, pair.Items)
+	require.Len(t, pair.TupleItems, 2)
+	require.Equal(t, Types{"string"}, pair.TupleItems[0].Type)
+
+	src, err := Generate(abi, "example")
+	require.NoError(t, err)
+	require.Contains(t, string(src), "type Pair = []json.RawMessage")
+	require.Contains(t, string(src), "Y *int64 `json:\"y,omitempty\"`")
+
+	abi, err = Parse([]byte(`{"body": {"functions": [], "root_schema": {"definitions": {
+		"Balance": {"type": "integer", "format": "uint128", "minimum": 0}
+	}}}}`))
+	require.NoError(t, err)
+	src, err = Generate(abi, "example")
+	require.NoError(t, err)
+	require.Contains(t, string(src), "type Balance = json.Number")
+
+	abi, err = Parse([]byte(`{"body": {"functions": [], "root_schema": {"definitions": {
+		"Big": {"type": "integer", "format": "uint256"}
+	}}}}`))
+	require.NoError(t, err)
+	_, err = Generate(abi, "example")
+	require.EqualError(t, err, "generating definition Big: unsupported integer format uint256")
+}
+
+func TestNames(t *testing.T) {
+	require.Equal(t, "GetAccountID", exportedName("get_account_id"))
+	require.Equal(t, "AccountID", exportedName("AccountId"))
+	require.Equal(t, "HTTPServer", exportedName("HTTPServer"))
+	require.Equal(t, "TokenIDs", exportedName("TokenIds"))
+	require.Equal(t, "V2Pool", exportedName("v2Pool"))
+	require.Equal(t, "accountID", paramName("accountId"))
+	require.Equal(t, "X2fa", exportedName("2fa"))
+	require.Equal(t, "accountID", paramName("account_id"))
+	require.Equal(t, "idList", paramName("id_list"))
+	require.Equal(t, "typeArg", paramName("type"))
+	require.Equal(t, "gasArg", paramName("gas"))
+}
@@ -0,0 +1,112 @@
+// Code generated by near-abigen. DO NOT EDIT.
+
+package counter
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+
+	api "github.com/textileio/near-api-go"
+	"github.com/textileio/near-api-go/account"
+	"github.com/textileio/near-api-go/contract"
+)
+
+// AccountID is the AccountId type of the contract.
+//
+// NEAR Account Identifier.
+type AccountID = string
+
+// Pair is the Pair type of the contract.
+type Pair struct {
+	// The first value.
+	A    uint32   `json:"a"`
+	B    *string  `json:"b,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// Status is the Status type of the contract.
+type Status string
+
+// Values of Status.
+const (
+	StatusActive Status = "Active"
+	StatusPaused Status = "Paused"
+)
+
+// U128 is the U128 type of the contract.
+type U128 = string
+
+// U64 is the U64 type of the contract.
+type U64 = string
+
+// Counter is a client of the counter contract.
+type Counter struct {
+	*contract.Contract
+}
+
+// NewCounter creates a Counter for the contract at contractID. View functions are called with the viewer,
+// and change functions are signed by the sender, which may be nil if only view functions are used.
+func NewCounter(contractID string, viewer api.Viewer, sender account.Sender) *Counter {
+	return &Counter{Contract: contract.New(contractID, viewer, sender)}
+}
+
+// New calls the change function new. The default gas is used if gas is zero.
+//
+// Initializes the counter.
+func (c *Counter) New(ctx context.Context, ownerID AccountID, gas uint64) error {
+	args := map[string]interface{}{
+		"owner_id": ownerID,
+	}
+	_, err := contract.Call[json.RawMessage](ctx, c.Contract, "new", args, gas, nil)
+	return err
+}
+
+// GetNum calls the view function get_num.
+//
+// Returns the current count.
+func (c *Counter) GetNum(ctx context.Context, opts ...api.CallFunctionOption) (int8, error) {
+	return contract.View[int8](ctx, c.Contract, "get_num", nil, opts...)
+}
+
+// GetGreeting calls the view function get_greeting.
+func (c *Counter) GetGreeting(ctx context.Context, accountID AccountID, opts ...api.CallFunctionOption) (*string, error) {
+	args := map[string]interface{}{
+		"account_id": accountID,
+	}
+	return contract.View[*string](ctx, c.Contract, "get_greeting", args, opts...)
+}
+
+// ListPairs calls the view function list_pairs.
+func (c *Counter) ListPairs(ctx context.Context, fromIndex *U64, limit *uint32, opts ...api.CallFunctionOption) ([]Pair, error) {
+	args := map[string]interface{}{
+		"from_index": fromIndex,
+		"limit":      limit,
+	}
+	return contract.View[[]Pair](ctx, c.Contract, "list_pairs", args, opts...)
+}
+
+// Balances calls the view function balances.
+func (c *Counter) Balances(ctx context.Context, opts ...api.CallFunctionOption) (map[string]U128, error) {
+	return contract.View[map[string]U128](ctx, c.Contract, "balances", nil, opts...)
+}
+
+// Increment calls the change function increment. The default gas is used if gas is zero.
+//
+// Increments the count and returns it.
+func (c *Counter) Increment(ctx context.Context, by uint32, gas uint64) (int8, error) {
+	args := map[string]interface{}{
+		"by": by,
+	}
+	return contract.Call[int8](ctx, c.Contract, "increment", args, gas, nil)
+}
+
+// AddPair calls the change function add_pair. The default gas is used if gas is zero.
+func (c *Counter) AddPair(ctx context.Context, pair Pair, status Status, gas uint64, deposit *big.Int) error {
+	args := map[string]interface{}{
+		"pair":   pair,
+		"status": status,
+	}
+	_, err := contract.Call[json.RawMessage](ctx, c.Contract, "add_pair", args, gas, deposit)
+	return err
+}
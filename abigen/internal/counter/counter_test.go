@@ -0,0 +1,103 @@
+package counter
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	api "github.com/textileio/near-api-go"
+	"github.com/textileio/near-api-go/account"
+	"github.com/textileio/near-api-go/keys"
+	"github.com/textileio/near-api-go/neartest"
+)
+
+var ctx = context.Background()
+
+func TestCounter(t *testing.T) {
+	server := neartest.NewServer()
+	defer server.Close()
+	signer, err := keys.NewKeyPairFromRandom("ed25519")
+	require.NoError(t, err)
+	server.AddAccount("alice.test", big.NewInt(1000))
+	server.AddAccessKey("alice.test", signer.GetPublicKey())
+	server.AddAccount("counter.test", new(big.Int))
+
+	server.RegisterCall("counter.test", "increment", func(c *neartest.Call) ([]byte, error) {
+		var args struct {
+			By uint32 `json:"by"`
+		}
+		if err := json.Unmarshal(c.Args, &args); err != nil {
+			return nil, err
+		}
+		v, _ := c.Get([]byte("num"))
+		n, _ := strconv.Atoi(string(v))
+		n += int(args.By)
+		c.Set([]byte("num"), []byte(strconv.Itoa(n)))
+		return json.Marshal(n)
+	})
+	server.RegisterView("counter.test", "get_num", func(v *neartest.View) ([]byte, error) {
+		n, _ := v.Get([]byte("num"))
+		return n, nil
+	})
+	server.RegisterCall("counter.test", "add_pair", func(c *neartest.Call) ([]byte, error) {
+		var args struct {
+			Pair   Pair   `json:"pair"`
+			Status Status `json:"status"`
+		}
+		if err := json.Unmarshal(c.Args, &args); err != nil {
+			return nil, err
+		}
+		pair, err := json.Marshal([]Pair{args.Pair})
+		if err != nil {
+			return nil, err
+		}
+		c.Set([]byte("pairs"), pair)
+		c.Set([]byte("status"), []byte(args.Status))
+		return nil, nil
+	})
+	server.RegisterView("counter.test", "list_pairs", func(v *neartest.View) ([]byte, error) {
+		var args map[string]interface{}
+		if err := json.Unmarshal(v.Args, &args); err != nil {
+			return nil, err
+		}
+		if args["from_index"] != nil || args["limit"] != float64(10) {
+			return nil, nil
+		}
+		pairs, _ := v.Get([]byte("pairs"))
+		return pairs, nil
+	})
+	server.RegisterView("counter.test", "get_greeting", func(v *neartest.View) ([]byte, error) {
+		return []byte("null"), nil
+	})
+
+	config, err := server.Config(signer)
+	require.NoError(t, err)
+	defer config.RPCClient.Close()
+	client, err := api.NewClient(config)
+	require.NoError(t, err)
+	counter := NewCounter("counter.test", client, account.NewAccount(config, "alice.test"))
+	require.Equal(t, "counter.test", counter.ID())
+
+	n, err := counter.Increment(ctx, 3, 0)
+	require.NoError(t, err)
+	require.Equal(t, int8(3), n)
+	n, err = counter.GetNum(ctx, api.CallFunctionWithFinality("final"))
+	require.NoError(t, err)
+	require.Equal(t, int8(3), n)
+
+	b := "two"
+	pair := Pair{A: 1, B: &b, Tags: []string{"x"}}
+	require.NoError(t, counter.AddPair(ctx, pair, StatusPaused, 0, big.NewInt(10)))
+	require.Equal(t, big.NewInt(990), server.Balance("alice.test"))
+	limit := uint32(10)
+	pairs, err := counter.ListPairs(ctx, nil, &limit)
+	require.NoError(t, err)
+	require.Equal(t, []Pair{pair}, pairs)
+
+	greeting, err := counter.GetGreeting(ctx, "alice.test")
+	require.NoError(t, err)
+	require.Nil(t, greeting)
+}
@@ -0,0 +1,4 @@
+// Package counter is a client generated from the ABI of an example counter contract.
+package counter
+
+//go:generate go run ../../../cmd/near-abigen -abi ../../testdata/counter_abi.json -pkg counter -out counter.go
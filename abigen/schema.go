@@ -0,0 +1,124 @@
+package abigen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Schema is the subset of JSON schema generated by schemars for near-sdk ABIs.
+type Schema struct {
+	Ref         string            `json:"$ref,omitempty"`
+	Type        Types             `json:"type,omitempty"`
+	Format      string            `json:"format,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Enum        []json.RawMessage `json:"enum,omitempty"`
+	// Items is the schema of the elements of an array, or nil for tuples.
+	Items *Schema `json:"-"`
+	// TupleItems are the schemas of the elements of a tuple.
+	TupleItems []*Schema  `json:"-"`
+	Properties Properties `json:"properties,omitempty"`
+	Required   []string   `json:"required,omitempty"`
+	// AdditionalProperties is the schema of the values of a map.
+	AdditionalProperties *Schema            `json:"-"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Definitions          map[string]*Schema `json:"definitions,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Boolean schemas decode to the empty schema.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("true")) || bytes.Equal(data, []byte("false")) {
+		*s = Schema{}
+		return nil
+	}
+	type schema Schema
+	aux := struct {
+		*schema
+		Items                json.RawMessage `json:"items"`
+		AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	}{schema: (*schema)(s)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if len(aux.Items) > 0 {
+		if aux.Items[0] == '[' {
+			if err := json.Unmarshal(aux.Items, &s.TupleItems); err != nil {
+				return fmt.Errorf("unmarshaling tuple items: %v", err)
+			}
+		} else if err := json.Unmarshal(aux.Items, &s.Items); err != nil {
+			return fmt.Errorf("unmarshaling items: %v", err)
+		}
+	}
+	if len(aux.AdditionalProperties) > 0 && aux.AdditionalProperties[0] == '{' {
+		if err := json.Unmarshal(aux.AdditionalProperties, &s.AdditionalProperties); err != nil {
+			return fmt.Errorf("unmarshaling additional properties: %v", err)
+		}
+	}
+	return nil
+}
+
+// IsRequired reports whether the property is required.
+func (s *Schema) IsRequired(name string) bool {
+	for _, r := range s.Required {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Types are the allowed JSON types of a schema, encoded as a string or an array of strings.
+type Types []string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = Types{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+// Property is a named property of an object schema.
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+// Properties are the properties of an object schema, in the order they are declared.
+type Properties []Property
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (p *Properties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return fmt.Errorf("properties must be an object")
+	}
+	*p = nil
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("invalid property name %v", tok)
+		}
+		var s Schema
+		if err := dec.Decode(&s); err != nil {
+			return fmt.Errorf("unmarshaling property %s: %v", name, err)
+		}
+		*p = append(*p, Property{Name: name, Schema: &s})
+	}
+	_, err := dec.Token()
+	return err
+}
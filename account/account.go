@@ -5,14 +5,16 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"strings"
 	"time"
 
-	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/mr-tron/base58/base58"
 	"github.com/near/borsh-go"
 	logging "github.com/textileio/go-log/v2"
+	"github.com/textileio/near-api-go/internal/call"
 	itypes "github.com/textileio/near-api-go/internal/types"
+	"github.com/textileio/near-api-go/jsonrpc"
 	"github.com/textileio/near-api-go/keys"
 	"github.com/textileio/near-api-go/transaction"
 	"github.com/textileio/near-api-go/types"
@@ -53,16 +55,11 @@ func (a *Account) ViewState(ctx context.Context, opts ...ViewStateOption) (*Acco
 	for _, opt := range opts {
 		opt(req)
 	}
-	if req.BlockID == nil && req.Finality == "" {
-		return nil, fmt.Errorf("you must provide ViewStateWithBlockHeight, ViewStateWithBlockHash or ViewStateWithFinality")
-	}
-	if req.BlockID != nil && req.Finality != "" {
-		return nil, fmt.Errorf(
-			"you must provide one of ViewStateWithBlockHeight, ViewStateWithBlockHash or ViewStateWithFinality",
-		)
+	if err := itypes.CheckBlockReference(req.Finality, req.BlockID); err != nil {
+		return nil, err
 	}
 	var res AccountStateView
-	if err := a.config.RPCClient.CallContext(ctx, &res, "query", rpc.NewNamedParams(req)); err != nil {
+	if err := call.Block(ctx, a.config, &res, "query", jsonrpc.NewNamedParams(req)); err != nil {
 		return nil, fmt.Errorf("calling rpc: %v", util.MapRPCError(err))
 	}
 	return &res, nil
@@ -81,18 +78,11 @@ func (a *Account) State(
 	for _, opt := range opts {
 		opt(req)
 	}
-	if req.BlockID == nil && req.Finality == "" {
-		return nil, fmt.Errorf(
-			"you must provide ViewAccountWithBlockHeight, ViewAccountWithBlockHash or ViewAccountWithFinality",
-		)
-	}
-	if req.BlockID != nil && req.Finality != "" {
-		return nil, fmt.Errorf(
-			"you must provide one of ViewAccountWithBlockHeight, ViewAccountWithBlockHash or ViewAccountWithFinality",
-		)
+	if err := itypes.CheckBlockReference(req.Finality, req.BlockID); err != nil {
+		return nil, err
 	}
 	var res AccountView
-	if err := a.config.RPCClient.CallContext(ctx, &res, "query", rpc.NewNamedParams(req)); err != nil {
+	if err := call.Block(ctx, a.config, &res, "query", jsonrpc.NewNamedParams(req)); err != nil {
 		return nil, fmt.Errorf("calling rpc: %v", util.MapRPCError(err))
 	}
 	return &res, nil
@@ -114,18 +104,43 @@ func (a *Account) FindAccessKey(
 
 	pubKey := a.config.Signer.GetPublicKey()
 
-	// TODO: Lookup answer in a cache first.
+	key, err := a.accessKeyCacheKey(&pubKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if a.config.Cache != nil {
+		if cached, ok := a.config.Cache.AccessKey(key); ok {
+			view := cached.(AccessKeyView)
+			return &pubKey, &view, nil
+		}
+	}
 
 	ret, err := a.ViewAccessKey(ctx, &pubKey)
 	if err != nil {
 		return nil, nil, fmt.Errorf("viewing access key: %v", err)
 	}
+	if a.config.Cache != nil {
+		a.config.Cache.SetAccessKey(key, *ret)
+	}
 
 	return &pubKey, ret, nil
 }
 
+func (a *Account) accessKeyCacheKey(pubKey *keys.PublicKey) (string, error) {
+	pubKeyStr, err := pubKey.ToString()
+	if err != nil {
+		return "", fmt.Errorf("converting public key to string: %v", err)
+	}
+	return a.accountID + ":" + pubKeyStr, nil
+}
+
 // ViewAccessKey gets the access key view for the provided public key associated with the account.
-func (a *Account) ViewAccessKey(ctx context.Context, pubKey *keys.PublicKey) (*AccessKeyView, error) {
+// The latest optimistic block is used unless ViewAccessKeyWithBlockReference is provided.
+func (a *Account) ViewAccessKey(
+	ctx context.Context,
+	pubKey *keys.PublicKey,
+	opts ...ViewAccessKeyOption,
+) (*AccessKeyView, error) {
 	pubKeyStr, err := pubKey.ToString()
 	if err != nil {
 		return nil, fmt.Errorf("converting public key to string: %v", err)
@@ -137,44 +152,21 @@ func (a *Account) ViewAccessKey(ctx context.Context, pubKey *keys.PublicKey) (*A
 		PublicKey:   pubKeyStr,
 		Finality:    "optimistic",
 	}
-
-	type viewAccessKeyResp struct {
-		itypes.QueryResponse
-		Nonce      uint64           `json:"nonce"`
-		Permission *json.RawMessage `json:"permission"`
-	}
-
-	var raw json.RawMessage
-	resp := &viewAccessKeyResp{Permission: &raw}
-
-	// var res AccessKeyView
-	if err := a.config.RPCClient.CallContext(ctx, &resp, "query", rpc.NewNamedParams(req)); err != nil {
-		return nil, fmt.Errorf("calling rpc: %v", util.MapRPCError(err))
+	for _, opt := range opts {
+		opt(req)
 	}
-	if resp.Error != "" {
-		return nil, fmt.Errorf("error returned in body: %s", resp.Error)
+	if err := itypes.CheckBlockReference(req.Finality, req.BlockID); err != nil {
+		return nil, err
 	}
 
-	ret := &AccessKeyView{
-		QueryResponse: itypes.QueryResponse{
-			BlockHash:   resp.BlockHash,
-			BlockHeight: resp.BlockHeight,
-		},
-		Nonce: resp.Nonce,
+	var res AccessKeyView
+	if err := call.Block(ctx, a.config, &res, "query", jsonrpc.NewNamedParams(req)); err != nil {
+		return nil, fmt.Errorf("calling rpc: %v", util.MapRPCError(err))
 	}
-
-	if string(raw) == "\"FullAccess\"" {
-		ret.PermissionType = FullAccessPermissionType
-	} else {
-		var view FunctionCallPermissionView
-		if err := json.Unmarshal(raw, &view); err != nil {
-			return nil, fmt.Errorf("unmarshaling permission: %v", err)
-		}
-		ret.FunctionCallPermissionView = &view
-		ret.PermissionType = FunctionCallPermissionType
+	if res.Error != "" {
+		return nil, fmt.Errorf("error returned in body: %s", res.Error)
 	}
-
-	return ret, nil
+	return &res, nil
 }
 
 // SignTransaction creates and signs a transaction from the supplied actions.
@@ -186,8 +178,14 @@ func (a *Account) SignTransaction(
 	if a.config.Signer == nil {
 		return nil, nil, fmt.Errorf("no signer configured")
 	}
+	if err := types.AccountID(a.accountID).Validate(); err != nil {
+		return nil, nil, fmt.Errorf("validating signer id: %v", err)
+	}
+	if err := types.AccountID(receiverID).Validate(); err != nil {
+		return nil, nil, fmt.Errorf("validating receiver id: %v", err)
+	}
 
-	_, accessKeyView, err := a.FindAccessKey(ctx, receiverID, actions)
+	pubKey, accessKeyView, err := a.FindAccessKey(ctx, receiverID, actions)
 	if err != nil {
 		return nil, nil, fmt.Errorf("finding access key: %v", err)
 	}
@@ -195,11 +193,12 @@ func (a *Account) SignTransaction(
 		return nil, nil, fmt.Errorf("no access key view found") // TODO: Better error message.
 	}
 	var res itypes.BlockResult
-	if err := a.config.RPCClient.CallContext(
+	if err := call.Block(
 		ctx,
+		a.config,
 		&res,
 		"block",
-		rpc.NewNamedParams(itypes.BlockRequest{Finality: "final"}),
+		jsonrpc.NewNamedParams(itypes.BlockRequest{Finality: "final"}),
 	); err != nil {
 		return nil, nil, fmt.Errorf("calling block rpc: %v", util.MapRPCError(err))
 	}
@@ -210,6 +209,16 @@ func (a *Account) SignTransaction(
 	var blockHashArr [32]byte
 	copy(blockHashArr[:], blockHash)
 	nonce := accessKeyView.Nonce + 1
+	if a.config.Cache != nil {
+		// The next transaction must use the following nonce.
+		key, err := a.accessKeyCacheKey(pubKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		view := *accessKeyView
+		view.Nonce = nonce
+		a.config.Cache.UpdateAccessKey(key, view)
+	}
 
 	pk := a.config.Signer.GetPublicKey()
 	var dataArr [32]byte
@@ -240,24 +249,42 @@ func (a *Account) SignAndSendTransaction(
 	actions ...transaction.Action,
 ) (*FinalExecutionOutcome, error) {
 	var result *FinalExecutionOutcome
-	if err := util.Retry(nonceRetryCount, nonceRetryWait, nonceRetryBackoff, func(done *bool) error {
+	var nonces []uint64
+	if err := util.RetryContext(ctx, nonceRetryCount, nonceRetryWait, nonceRetryBackoff, func(
+		ctx context.Context,
+		done *bool,
+	) error {
 		txHash, signedTransaction, err := a.SignTransaction(ctx, receiverID, actions...)
 		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			return fmt.Errorf("signing transaction: %v", err)
 		}
+		nonces = append(nonces, signedTransaction.Transaction.Nonce)
 		bytes, err := borsh.Serialize(*signedTransaction)
 		if err != nil {
 			return fmt.Errorf("serializing signed transaction: %v", err)
 		}
 		var res FinalExecutionOutcome
-		if err := a.config.RPCClient.CallContext(
+		if err := call.RPC(
 			ctx,
+			a.config,
 			&res,
 			"broadcast_tx_commit",
 			base64.StdEncoding.EncodeToString(bytes),
 		); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			mappedErr := util.MapRPCError(err)
 			if strings.Contains(mappedErr.Error(), "InvalidNonce") {
+				if a.config.Cache != nil {
+					pubKey := a.config.Signer.GetPublicKey()
+					if key, err := a.accessKeyCacheKey(&pubKey); err == nil {
+						a.config.Cache.InvalidateAccessKey(key)
+					}
+				}
 				// Swallow the error and let Retry continue.
 				log.Warnf("Retrying transaction %s:%s with new nonce.", receiverID, base58.Encode(txHash))
 				return nil
@@ -268,7 +295,7 @@ func (a *Account) SignAndSendTransaction(
 		*done = true
 		return nil
 	}); err != nil {
-		return nil, fmt.Errorf("signing and sending transaction: %v", err)
+		return nil, fmt.Errorf("signing and sending transaction with nonces %v: %w", nonces, err)
 	}
 	if result == nil {
 		return nil, fmt.Errorf("failed to send transaction, but no error was returned")
@@ -325,7 +352,7 @@ func (a *Account) FunctionCall(
 	}
 	res, err := a.SignAndSendTransaction(ctx, contractID, *action)
 	if err != nil {
-		return nil, fmt.Errorf("signing and sending transaction: %v", err)
+		return nil, fmt.Errorf("signing and sending transaction: %w", err)
 	}
 	return res, nil
 }
@@ -335,7 +362,20 @@ func (a *Account) DeployContract(ctx context.Context, code []byte) (*FinalExecut
 	action := transaction.DeployContractAction(code)
 	res, err := a.SignAndSendTransaction(ctx, a.accountID, action)
 	if err != nil {
-		return nil, fmt.Errorf("signing and sending transaction: %v", err)
+		return nil, fmt.Errorf("signing and sending transaction: %w", err)
+	}
+	return res, nil
+}
+
+// SendMoney transfers amount yoctoNEAR to the receiver. Transfers to an implicit account id
+// create the account if it doesn't exist.
+func (a *Account) SendMoney(ctx context.Context, receiverID string, amount big.Int) (*FinalExecutionOutcome, error) {
+	if err := types.AccountID(receiverID).Validate(); err != nil {
+		return nil, fmt.Errorf("validating receiver id: %v", err)
+	}
+	res, err := a.SignAndSendTransaction(ctx, receiverID, transaction.TransferAction(amount))
+	if err != nil {
+		return nil, fmt.Errorf("signing and sending transaction: %w", err)
 	}
 	return res, nil
 }
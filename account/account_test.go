@@ -2,11 +2,22 @@ package account
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"time"
 
-	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/near/borsh-go"
 	"github.com/stretchr/testify/require"
+	"github.com/textileio/near-api-go/cache"
+	"github.com/textileio/near-api-go/jsonrpc"
 	"github.com/textileio/near-api-go/keys"
+	"github.com/textileio/near-api-go/neartest"
+	"github.com/textileio/near-api-go/transaction"
 	"github.com/textileio/near-api-go/types"
+	"github.com/textileio/near-api-go/util"
 
 	"testing"
 )
@@ -19,30 +30,33 @@ func TestIt(t *testing.T) {
 	require.NotNil(t, a)
 }
 
-// func TestViewState(t *testing.T) {
-// 	a, cleanup := makeAccount(t)
-// 	defer cleanup()
-// 	res, err := a.ViewState(ctx, ViewStateWithFinality("final"))
-// 	require.NoError(t, err)
-// 	require.NotNil(t, res)
-// }
-
-// func TestState(t *testing.T) {
-// 	a, cleanup := makeAccount(t)
-// 	defer cleanup()
-// 	res, err := a.State(ctx, StateWithFinality("final"))
-// 	require.NoError(t, err)
-// 	require.NotNil(t, res)
-// }
-
-// func TestFindAccessKey(t *testing.T) {
-// 	a, cleanup := makeAccount(t)
-// 	defer cleanup()
-// 	pubKey, accessKeyView, err := a.FindAccessKey(ctx, "", nil)
-// 	require.NoError(t, err)
-// 	require.NotNil(t, pubKey)
-// 	require.NotNil(t, accessKeyView)
-// }
+func TestViewState(t *testing.T) {
+	a, cleanup := makeAccount(t)
+	defer cleanup()
+	res, err := a.ViewState(ctx, ViewStateWithFinality("final"), ViewStateWithPrefix("co"))
+	require.NoError(t, err)
+	require.Len(t, res.Values, 1)
+	key, err := base64.StdEncoding.DecodeString(res.Values[0].Key)
+	require.NoError(t, err)
+	require.Equal(t, "count", string(key))
+}
+
+func TestState(t *testing.T) {
+	a, cleanup := makeAccount(t)
+	defer cleanup()
+	res, err := a.State(ctx, StateWithFinality("final"))
+	require.NoError(t, err)
+	require.Equal(t, "1000000000000000000000000", res.Amount)
+}
+
+func TestFindAccessKey(t *testing.T) {
+	a, cleanup := makeAccount(t)
+	defer cleanup()
+	pubKey, accessKeyView, err := a.FindAccessKey(ctx, "", nil)
+	require.NoError(t, err)
+	require.NotNil(t, pubKey)
+	require.NotNil(t, accessKeyView)
+}
 
 func TestViewAccessKey(t *testing.T) {
 	a, cleanup := makeAccount(t)
@@ -59,50 +73,349 @@ func TestViewAccessKey(t *testing.T) {
 	require.Error(t, err)
 }
 
-// func TestSignTransaction(t *testing.T) {
-// 	a, cleanup := makeAccount(t)
-// 	defer cleanup()
-// 	amt := big.NewInt(1000)
-// 	sendAction := transaction.TransferAction(*amt)
-// 	hash, signedTxn, err := a.SignTransaction(ctx, "carsonfarmer.testnet", sendAction)
-// 	require.NoError(t, err)
-// 	require.NotEmpty(t, hash)
-// 	require.NotNil(t, signedTxn)
-// }
-
-// func TestSignAndSendTransaction(t *testing.T) {
-// 	a, cleanup := makeAccount(t)
-// 	defer cleanup()
-// 	amt, ok := (&big.Int{}).SetString("1000000000000000000000000", 10)
-// 	require.True(t, ok)
-// 	sendAction := transaction.TransferAction(*amt)
-// 	res, err := a.SignAndSendTransaction(ctx, "carsonfarmer.testnet", sendAction)
-// 	require.NoError(t, err)
-// 	require.NotNil(t, res)
-
-// 	status, ok := res.GetStatus()
-// 	fmt.Println(status, ok)
-
-// 	status2, ok := res.GetStatusBasic()
-// 	fmt.Println(status2, ok)
-// }
+func TestSignTransaction(t *testing.T) {
+	a, cleanup := makeAccount(t)
+	defer cleanup()
+	amt := big.NewInt(1000)
+	sendAction := transaction.TransferAction(*amt)
+	hash, signedTxn, err := a.SignTransaction(ctx, "carsonfarmer.testnet", sendAction)
+	require.NoError(t, err)
+	require.NotEmpty(t, hash)
+	require.NotNil(t, signedTxn)
+}
 
-func makeAccount(t *testing.T) (*Account, func()) {
-	rpcClient, err := rpc.DialContext(ctx, "https://rpc.testnet.near.org")
+func TestSignAndSendTransaction(t *testing.T) {
+	a, cleanup := makeAccount(t)
+	defer cleanup()
+	amt, ok := (&big.Int{}).SetString("1000000000000000000000", 10)
+	require.True(t, ok)
+	sendAction := transaction.TransferAction(*amt)
+	res, err := a.SignAndSendTransaction(ctx, "carsonfarmer.testnet", sendAction)
 	require.NoError(t, err)
+	require.NotNil(t, res)
 
-	// keys, err := keys.NewKeyPairFromString(
-	// 	"ed25519:xxxx",
-	// )
-	// require.NoError(t, err)
+	status, ok := res.GetStatus()
+	require.True(t, ok)
+	require.Nil(t, status.Failure)
 
-	config := &types.Config{
-		RPCClient: rpcClient,
-		NetworkID: "testnet",
-		// Signer:    keys,
-	}
+	state, err := a.State(ctx, StateWithFinality("final"))
+	require.NoError(t, err)
+	require.Equal(t, "999000000000000000000000", state.Amount)
+}
+
+func TestSendMoney(t *testing.T) {
+	a, cleanup := makeAccount(t)
+	defer cleanup()
+	userKey, err := keys.NewKeyPairFromRandom("ed25519")
+	require.NoError(t, err)
+	implicitID, err := types.ImplicitAccountID(userKey.GetPublicKey())
+	require.NoError(t, err)
+
+	_, err = a.SendMoney(ctx, implicitID.String(), *big.NewInt(1000))
+	require.NoError(t, err)
+	state, err := NewAccount(a.config, implicitID.String()).State(ctx, StateWithFinality("final"))
+	require.NoError(t, err)
+	require.Equal(t, "1000", state.Amount)
+
+	_, err = a.SendMoney(ctx, "CarsonFarmer.testnet", *big.NewInt(1000))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid character")
+	_, err = a.SignAndSendTransaction(ctx, "carsonfarmer..testnet", transaction.TransferAction(*big.NewInt(1)))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "validating receiver id")
+}
+
+func TestStakedBalance(t *testing.T) {
+	var params []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage        `json:"id"`
+			Params map[string]interface{} `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		params = append(params, req.Params)
+		res := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		if req.Params["account_id"] == "broken.pool" {
+			res["result"] = map[string]interface{}{"error": "wasm execution failed", "result": []byte{}}
+		} else {
+			res["result"] = map[string]interface{}{"result": []byte(`"100"`)}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(res))
+	}))
+	defer server.Close()
+	rpcClient, err := jsonrpc.DialHTTP(server.URL)
+	require.NoError(t, err)
+	defer rpcClient.Close()
+	a := NewAccount(&types.Config{RPCClient: rpcClient}, "alice.testnet")
+
+	balance, err := a.StakedBalance(ctx, "pool.testnet")
+	require.NoError(t, err)
+	require.Equal(t, "100", balance.String())
+	require.Equal(t, "final", params[0]["finality"])
+	require.Nil(t, params[0]["block_id"])
+
+	_, err = a.UnstakedBalance(ctx, "pool.testnet", StakingWithBlockHeight(5))
+	require.NoError(t, err)
+	require.Equal(t, "get_account_unstaked_balance", params[1]["method_name"])
+	require.Equal(t, float64(5), params[1]["block_id"])
+	require.Nil(t, params[1]["finality"])
+
+	_, err = a.StakedBalance(ctx, "broken.pool")
+	require.EqualError(t, err, "error returned in body: wasm execution failed")
+	_, err = a.IsUnstakedBalanceAvailable(ctx, "pool.testnet", StakingWithBlockReference(types.BlockReference{}))
+	require.ErrorIs(t, err, types.ErrNoBlockReference)
+}
+
+func TestReceiptTree(t *testing.T) {
+	var outcome FinalExecutionOutcome
+	err := json.Unmarshal([]byte(`{
+		"status": {"Failure": {}},
+		"transaction_outcome": {
+			"id": "tx",
+			"outcome": {
+				"executor_id": "alice.testnet",
+				"receipt_ids": ["r1"],
+				"gas_burnt": 1,
+				"status": {"SuccessReceiptId": "r1"}
+			}
+		},
+		"receipts_outcome": [
+			{
+				"id": "r2",
+				"outcome": {
+					"executor_id": "token.testnet",
+					"logs": ["transfer failed"],
+					"receipt_ids": [],
+					"gas_burnt": 3,
+					"status": {"Failure": {"ActionError": {
+						"index": 0,
+						"kind": {"FunctionCallError": {"ExecutionError": "Smart contract panicked: oops"}}
+					}}}
+				}
+			},
+			{
+				"id": "r1",
+				"outcome": {"executor_id": "dex.testnet", "receipt_ids": ["r2"], "gas_burnt": 2, "status": {"SuccessValue": ""}}
+			},
+			{
+				"id": "r3",
+				"outcome": {"executor_id": "alice.testnet", "receipt_ids": [], "gas_burnt": 0, "status": "Pending"}
+			}
+		]
+	}`), &outcome)
+	require.NoError(t, err)
+
+	root := outcome.ReceiptTree()
+	require.Equal(t, "tx", root.ID)
+	require.Len(t, root.Children, 2)
+	r1 := root.Children[0]
+	require.Equal(t, "r1", r1.ID)
+	require.False(t, r1.Failed())
+	require.Len(t, r1.Children, 1)
+	r2 := r1.Children[0]
+	require.True(t, r2.Failed())
+	require.Equal(t, "ActionError", r2.Failure.Type)
+	require.Equal(t, 0, *r2.Failure.ActionIndex)
+	require.Equal(t, []string{"FunctionCallError", "ExecutionError"}, r2.Failure.Kind)
+	require.Equal(t, "Smart contract panicked: oops", r2.Failure.Message)
+	r3 := root.Children[1]
+	require.Equal(t, "r3", r3.ID)
+	require.False(t, r3.Failed())
+	require.Equal(t, ExecutionStatusBasicPending, *r3.StatusBasic)
+
+	rendered := root.String()
+	require.Contains(t, rendered, "└── r2 token.testnet gas=3 FAILURE ActionError.FunctionCallError.ExecutionError")
+	require.Contains(t, rendered, "log: transfer failed")
+	require.Contains(t, rendered, "├── r1 dex.testnet gas=2 SUCCESS")
+	require.Contains(t, rendered, "└── r3 alice.testnet gas=0 PENDING")
+}
+
+func TestDecodeFailure(t *testing.T) {
+	f := DecodeFailure(map[string]interface{}{
+		"InvalidTxError": map[string]interface{}{
+			"InvalidNonce": map[string]interface{}{"ak_nonce": 2.0, "tx_nonce": 1.0},
+		},
+	})
+	require.Equal(t, "InvalidTxError", f.Type)
+	require.Nil(t, f.ActionIndex)
+	require.Equal(t, []string{"InvalidNonce"}, f.Kind)
+	require.Equal(t, `{"ak_nonce":2,"tx_nonce":1}`, f.Message)
+
+	f = DecodeFailure(map[string]interface{}{
+		"ActionError": map[string]interface{}{"index": 1.0, "kind": "DeleteActionMustBeFinal"},
+	})
+	require.Equal(t, "ActionError.DeleteActionMustBeFinal (action 1)", f.String())
+
+	f = DecodeFailure(map[string]interface{}{})
+	require.NotNil(t, f)
+	require.Equal(t, "Failure", f.String())
+
+	require.Nil(t, DecodeFailure(nil))
+}
+
+func makeAccount(t *testing.T) (*Account, func()) {
+	server := neartest.NewServer()
+	signer, err := keys.NewKeyPairFromRandom("ed25519")
+	require.NoError(t, err)
+	pkey, err := keys.NewPublicKeyFromString("ed25519:H9k5eiU4xXS3M4z8HzKJSLaZdqGdGwBG49o7orNC4eZW")
+	require.NoError(t, err)
+	amt, ok := (&big.Int{}).SetString("1000000000000000000000000", 10)
+	require.True(t, ok)
+	server.AddAccount("client.chainlink.testnet", amt)
+	server.AddAccessKey("client.chainlink.testnet", signer.GetPublicKey())
+	server.AddAccessKey("client.chainlink.testnet", *pkey)
+	server.SetState("client.chainlink.testnet", []byte("count"), []byte("1"))
+	server.SetState("client.chainlink.testnet", []byte("owner"), []byte("carsonfarmer.testnet"))
+	server.AddAccount("carsonfarmer.testnet", new(big.Int))
+
+	config, err := server.Config(signer)
+	require.NoError(t, err)
 	a := NewAccount(config, "client.chainlink.testnet")
 	return a, func() {
-		rpcClient.Close()
+		config.RPCClient.Close()
+		server.Close()
+	}
+}
+
+func TestSignAndSendTransactionCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		res := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "query":
+			res["result"] = map[string]interface{}{"nonce": 5, "permission": "FullAccess"}
+		case "block":
+			res["result"] = map[string]interface{}{
+				"header": map[string]interface{}{"hash": "11111111111111111111111111111111"},
+			}
+		default:
+			res["error"] = map[string]interface{}{"code": -32000, "message": "Server error", "data": "InvalidNonce"}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(res))
+	}))
+	defer server.Close()
+	rpcClient, err := jsonrpc.DialHTTP(server.URL)
+	require.NoError(t, err)
+	defer rpcClient.Close()
+	signer, err := keys.NewKeyPairFromRandom("ed25519")
+	require.NoError(t, err)
+	a := NewAccount(&types.Config{RPCClient: rpcClient, Signer: signer, NetworkID: "testnet"}, "alice.testnet")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 700*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = a.SignAndSendTransaction(ctx, "bob.testnet")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, int64(time.Since(start)), int64(2*time.Second))
+	var retryErr *util.RetryError
+	require.ErrorAs(t, err, &retryErr)
+	require.Equal(t, 2, retryErr.Attempts)
+	require.Contains(t, err.Error(), "nonces [6 6]")
+}
+
+func TestFunctionCallCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		res := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "query":
+			res["result"] = map[string]interface{}{"nonce": 5, "permission": "FullAccess"}
+		case "block":
+			res["result"] = map[string]interface{}{
+				"header": map[string]interface{}{"hash": "11111111111111111111111111111111"},
+			}
+		default:
+			cancel()
+			res["error"] = map[string]interface{}{"code": -32000, "message": "Server error", "data": "InvalidNonce"}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(res))
+	}))
+	defer server.Close()
+	rpcClient, err := jsonrpc.DialHTTP(server.URL)
+	require.NoError(t, err)
+	defer rpcClient.Close()
+	signer, err := keys.NewKeyPairFromRandom("ed25519")
+	require.NoError(t, err)
+	a := NewAccount(&types.Config{RPCClient: rpcClient, Signer: signer, NetworkID: "testnet"}, "alice.testnet")
+
+	_, err = a.FunctionCall(ctx, "bob.testnet", "method")
+	require.ErrorIs(t, err, context.Canceled)
+	var retryErr *util.RetryError
+	require.ErrorAs(t, err, &retryErr)
+	require.Equal(t, 1, retryErr.Attempts)
+}
+
+func TestAccessKeyViewUnmarshal(t *testing.T) {
+	var full AccessKeyView
+	require.NoError(t, json.Unmarshal([]byte(`{"nonce": 3, "permission": "FullAccess", "block_height": 9}`), &full))
+	require.Equal(t, uint64(3), full.Nonce)
+	require.Equal(t, 9, full.BlockHeight)
+	require.Equal(t, FullAccessPermissionType, full.PermissionType)
+	require.Nil(t, full.FunctionCallPermissionView)
+
+	var fn AccessKeyView
+	require.NoError(t, json.Unmarshal([]byte(`{"nonce": 4, "permission": {"FunctionCall": {
+		"allowance": "100", "receiver_id": "contract.testnet", "method_names": ["a"]
+	}}}`), &fn))
+	require.Equal(t, FunctionCallPermissionType, fn.PermissionType)
+	require.Equal(t, "contract.testnet", fn.FunctionCallPermissionView.FunctionCall.ReceiverID)
+}
+
+func TestSignAndSendTransactionCachedAccessKey(t *testing.T) {
+	queries := 0
+	var nonces []uint64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		res := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "query":
+			queries++
+			res["result"] = map[string]interface{}{"nonce": 5, "permission": "FullAccess"}
+		case "block":
+			res["result"] = map[string]interface{}{
+				"header": map[string]interface{}{"hash": "11111111111111111111111111111111", "height": 10},
+			}
+		case "broadcast_tx_commit":
+			var params []string
+			require.NoError(t, json.Unmarshal(req.Params, &params))
+			bytes, err := base64.StdEncoding.DecodeString(params[0])
+			require.NoError(t, err)
+			var signed transaction.SignedTransaction
+			require.NoError(t, borsh.Deserialize(&signed, bytes))
+			nonces = append(nonces, signed.Transaction.Nonce)
+			res["result"] = map[string]interface{}{"status": map[string]interface{}{"SuccessValue": ""}}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(res))
+	}))
+	defer server.Close()
+	rpcClient, err := jsonrpc.DialHTTP(server.URL)
+	require.NoError(t, err)
+	defer rpcClient.Close()
+	signer, err := keys.NewKeyPairFromRandom("ed25519")
+	require.NoError(t, err)
+	config := &types.Config{RPCClient: rpcClient, Signer: signer, NetworkID: "testnet", Cache: cache.New()}
+	a := NewAccount(config, "alice.testnet")
+
+	for i := 0; i < 3; i++ {
+		_, err = a.SignAndSendTransaction(ctx, "bob.testnet")
+		require.NoError(t, err)
 	}
+	require.Equal(t, 1, queries)
+	require.Equal(t, []uint64{6, 7, 8}, nonces)
+	require.Equal(t, uint64(2), config.Cache.Stats().AccessKeyHits)
 }
@@ -0,0 +1,172 @@
+package account
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/textileio/near-api-go/keys"
+	"github.com/textileio/near-api-go/transaction"
+	"github.com/textileio/near-api-go/types"
+)
+
+// AccountCreator creates accounts.
+type AccountCreator interface {
+	// CreateAccount creates an account with a full access key.
+	CreateAccount(ctx context.Context, newAccountID string, publicKey keys.PublicKey) error
+}
+
+// registrarAccountID is the only account allowed to create top level accounts that aren't implicit.
+const registrarAccountID types.AccountID = "registrar"
+
+// LocalAccountCreator creates accounts by sending transactions signed by a master account.
+// The new accounts must be sub-accounts of the master account, implicit accounts, or top level
+// accounts if the master account is the registrar. Implicit accounts are created by transferring
+// the initial balance to them, so the public key must be the one the account id is derived from.
+// Eth-implicit accounts, like "0x" followed by 40 hex characters, aren't supported, since they
+// can't get the full access key of an ed25519 public key.
+type LocalAccountCreator struct {
+	master         Sender
+	masterID       types.AccountID
+	initialBalance *big.Int
+}
+
+var _ AccountCreator = (*LocalAccountCreator)(nil)
+
+// NewLocalAccountCreator creates a LocalAccountCreator transferring the initial balance from
+// the master account with id masterID to every new account.
+func NewLocalAccountCreator(master Sender, masterID string, initialBalance *big.Int) *LocalAccountCreator {
+	return &LocalAccountCreator{master: master, masterID: types.AccountID(masterID), initialBalance: initialBalance}
+}
+
+// CreateAccount implements AccountCreator.
+func (c *LocalAccountCreator) CreateAccount(ctx context.Context, newAccountID string, publicKey keys.PublicKey) error {
+	id, err := types.ParseAccountID(newAccountID)
+	if err != nil {
+		return fmt.Errorf("validating account id: %v", err)
+	}
+	balance := new(big.Int)
+	if c.initialBalance != nil {
+		balance.Set(c.initialBalance)
+	}
+	if id.IsEthImplicit() {
+		return fmt.Errorf("%s is an eth-implicit account, which isn't supported", id)
+	}
+	if id.IsImplicit() {
+		implicitID, err := types.ImplicitAccountID(publicKey)
+		if err != nil {
+			return fmt.Errorf("getting implicit account id: %v", err)
+		}
+		if implicitID != id {
+			return fmt.Errorf("%s isn't the implicit account id of the public key", id)
+		}
+		if _, err := c.master.SignAndSendTransaction(ctx, newAccountID, transaction.TransferAction(*balance)); err != nil {
+			return fmt.Errorf("signing and sending transaction: %v", err)
+		}
+		return nil
+	}
+	if id.IsTopLevel() && c.masterID != registrarAccountID {
+		return fmt.Errorf("%s is a top level account, which only the %s account can create", id, registrarAccountID)
+	}
+	if !id.IsTopLevel() && !id.IsSubAccountOf(c.masterID) {
+		return fmt.Errorf("%s isn't a sub-account of the master account %s", id, c.masterID)
+	}
+	fullAccess := transaction.AccessKey{
+		Permission: transaction.AccessKeyPermission{Enum: 1, FullAccess: transaction.FullAccessPermission{}},
+	}
+	if _, err := c.master.SignAndSendTransaction(
+		ctx,
+		newAccountID,
+		transaction.CreateAccountAction(),
+		transaction.TransferAction(*balance),
+		transaction.AddKeyAction(publicKey, fullAccess),
+	); err != nil {
+		return fmt.Errorf("signing and sending transaction: %v", err)
+	}
+	return nil
+}
+
+// URLAccountCreator creates accounts with the account endpoint of a NEAR contract helper.
+type URLAccountCreator struct {
+	helperURL  string
+	httpClient *http.Client
+}
+
+var _ AccountCreator = (*URLAccountCreator)(nil)
+
+// URLAccountCreatorOption controls the behavior of a URLAccountCreator.
+type URLAccountCreatorOption func(*URLAccountCreator)
+
+// URLAccountCreatorWithHTTPClient specifies the http.Client used to reach the contract helper.
+func URLAccountCreatorWithHTTPClient(client *http.Client) URLAccountCreatorOption {
+	return func(c *URLAccountCreator) {
+		c.httpClient = client
+	}
+}
+
+// NewURLAccountCreator creates a URLAccountCreator for the contract helper at helperURL.
+func NewURLAccountCreator(helperURL string, opts ...URLAccountCreatorOption) *URLAccountCreator {
+	c := &URLAccountCreator{
+		helperURL:  strings.TrimSuffix(helperURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// CreateAccount implements AccountCreator.
+func (c *URLAccountCreator) CreateAccount(ctx context.Context, newAccountID string, publicKey keys.PublicKey) error {
+	if err := types.AccountID(newAccountID).Validate(); err != nil {
+		return fmt.Errorf("validating account id: %v", err)
+	}
+	pubKeyStr, err := publicKey.ToString()
+	if err != nil {
+		return fmt.Errorf("converting public key to string: %v", err)
+	}
+	body, err := json.Marshal(map[string]string{
+		"newAccountId":        newAccountID,
+		"newAccountPublicKey": pubKeyStr,
+	})
+	if err != nil {
+		return fmt.Errorf("marshaling request: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.helperURL+"/account", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending request: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<16))
+		return fmt.Errorf("creating account with helper: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// NewAccountCreator creates an AccountCreator for the config. It's a LocalAccountCreator for
+// the master account if the config has one, or a URLAccountCreator for the helper url otherwise.
+func NewAccountCreator(config *types.Config) (AccountCreator, error) {
+	if config.MasterAccount != "" {
+		return NewLocalAccountCreator(
+			NewAccount(config, config.MasterAccount),
+			config.MasterAccount,
+			config.InitialBalance,
+		), nil
+	}
+	if config.HelperURL != "" {
+		return NewURLAccountCreator(config.HelperURL), nil
+	}
+	return nil, fmt.Errorf("no master account or helper url configured")
+}
@@ -0,0 +1,102 @@
+package account
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/textileio/near-api-go/keys"
+	"github.com/textileio/near-api-go/neartest"
+	"github.com/textileio/near-api-go/types"
+)
+
+func TestLocalAccountCreator(t *testing.T) {
+	server := neartest.NewServer()
+	defer server.Close()
+	signer, err := keys.NewKeyPairFromRandom("ed25519")
+	require.NoError(t, err)
+	server.AddAccount("master.test", big.NewInt(1000))
+	server.AddAccessKey("master.test", signer.GetPublicKey())
+	config, err := server.Config(signer)
+	require.NoError(t, err)
+	defer config.RPCClient.Close()
+	config.MasterAccount = "master.test"
+	config.InitialBalance = big.NewInt(100)
+
+	creator, err := NewAccountCreator(config)
+	require.NoError(t, err)
+	require.IsType(t, &LocalAccountCreator{}, creator)
+
+	userKey, err := keys.NewKeyPairFromRandom("ed25519")
+	require.NoError(t, err)
+	require.NoError(t, creator.CreateAccount(ctx, "alice.master.test", userKey.GetPublicKey()))
+	require.Equal(t, big.NewInt(100), server.Balance("alice.master.test"))
+	require.Equal(t, big.NewInt(900), server.Balance("master.test"))
+	_, ok := server.Nonce("alice.master.test", userKey.GetPublicKey())
+	require.True(t, ok)
+
+	err = creator.CreateAccount(ctx, "alice.master.test", userKey.GetPublicKey())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "AccountAlreadyExists")
+
+	err = creator.CreateAccount(ctx, "alice.other.test", userKey.GetPublicKey())
+	require.EqualError(t, err, "alice.other.test isn't a sub-account of the master account master.test")
+	err = creator.CreateAccount(ctx, "alice", userKey.GetPublicKey())
+	require.EqualError(t, err, "alice is a top level account, which only the registrar account can create")
+	err = creator.CreateAccount(ctx, "Alice.master.test", userKey.GetPublicKey())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "validating account id")
+
+	implicitID, err := types.ImplicitAccountID(userKey.GetPublicKey())
+	require.NoError(t, err)
+	require.NoError(t, creator.CreateAccount(ctx, implicitID.String(), userKey.GetPublicKey()))
+	require.Equal(t, big.NewInt(100), server.Balance(implicitID.String()))
+	_, ok = server.Nonce(implicitID.String(), userKey.GetPublicKey())
+	require.True(t, ok)
+	otherKey, err := keys.NewKeyPairFromRandom("ed25519")
+	require.NoError(t, err)
+	err = creator.CreateAccount(ctx, implicitID.String(), otherKey.GetPublicKey())
+	require.EqualError(t, err, implicitID.String()+" isn't the implicit account id of the public key")
+
+	ethID := "0xabcdef0123456789abcdef0123456789abcdef01"
+	err = creator.CreateAccount(ctx, ethID, userKey.GetPublicKey())
+	require.EqualError(t, err, ethID+" is an eth-implicit account, which isn't supported")
+	require.Nil(t, server.Balance(ethID))
+}
+
+func TestURLAccountCreator(t *testing.T) {
+	var created map[string]string
+	helper := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/account", r.URL.Path)
+		var req map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req["newAccountId"] == "taken.testnet" {
+			http.Error(w, "Account taken.testnet already exists.", http.StatusForbidden)
+			return
+		}
+		created = req
+	}))
+	defer helper.Close()
+
+	creator, err := NewAccountCreator(&types.Config{HelperURL: helper.URL + "/"})
+	require.NoError(t, err)
+	require.IsType(t, &URLAccountCreator{}, creator)
+
+	userKey, err := keys.NewKeyPairFromRandom("ed25519")
+	require.NoError(t, err)
+	pubKey := userKey.GetPublicKey()
+	pubKeyStr, err := pubKey.ToString()
+	require.NoError(t, err)
+	require.NoError(t, creator.CreateAccount(ctx, "alice.testnet", pubKey))
+	require.Equal(t, map[string]string{"newAccountId": "alice.testnet", "newAccountPublicKey": pubKeyStr}, created)
+
+	err = creator.CreateAccount(ctx, "taken.testnet", pubKey)
+	require.EqualError(t, err, "creating account with helper: 403 Forbidden: Account taken.testnet already exists.")
+
+	_, err = NewAccountCreator(&types.Config{})
+	require.Error(t, err)
+}
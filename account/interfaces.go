@@ -0,0 +1,41 @@
+package account
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/textileio/near-api-go/keys"
+	"github.com/textileio/near-api-go/transaction"
+)
+
+// Viewer queries the state of an account.
+type Viewer interface {
+	State(ctx context.Context, opts ...StateOption) (*AccountView, error)
+	ViewState(ctx context.Context, opts ...ViewStateOption) (*AccountStateView, error)
+	ViewAccessKey(ctx context.Context, pubKey *keys.PublicKey, opts ...ViewAccessKeyOption) (*AccessKeyView, error)
+}
+
+// Sender sends transactions signed by an account.
+type Sender interface {
+	SignAndSendTransaction(
+		ctx context.Context,
+		receiverID string,
+		actions ...transaction.Action,
+	) (*FinalExecutionOutcome, error)
+	FunctionCall(
+		ctx context.Context,
+		contractID,
+		methodName string,
+		opts ...transaction.FunctionCallOpton,
+	) (*FinalExecutionOutcome, error)
+	DeployContract(ctx context.Context, code []byte) (*FinalExecutionOutcome, error)
+	SendMoney(ctx context.Context, receiverID string, amount big.Int) (*FinalExecutionOutcome, error)
+}
+
+// AccountAPI is the API of an account implemented by Account.
+type AccountAPI interface {
+	Viewer
+	Sender
+}
+
+var _ AccountAPI = (*Account)(nil)
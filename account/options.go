@@ -4,30 +4,33 @@ import (
 	"encoding/base64"
 
 	itypes "github.com/textileio/near-api-go/internal/types"
+	"github.com/textileio/near-api-go/types"
 )
 
 // ViewStateOption controls the behavior when calling ViewState.
 type ViewStateOption func(*itypes.QueryRequest)
 
-// ViewStateWithFinality specifies the finality to be used when querying the state.
-func ViewStateWithFinality(finalaity string) ViewStateOption {
+// ViewStateWithBlockReference specifies the block to query the state at.
+func ViewStateWithBlockReference(ref types.BlockReference) ViewStateOption {
 	return func(qr *itypes.QueryRequest) {
-		qr.Finality = finalaity
+		qr.Finality = string(ref.Finality())
+		qr.BlockID = ref.BlockID()
 	}
 }
 
+// ViewStateWithFinality specifies the finality to be used when querying the state.
+func ViewStateWithFinality(finalaity string) ViewStateOption {
+	return ViewStateWithBlockReference(types.BlockFinality(types.Finality(finalaity)))
+}
+
 // ViewStateWithBlockHeight specifies the block height to query the state for.
 func ViewStateWithBlockHeight(blockHeight int) ViewStateOption {
-	return func(qr *itypes.QueryRequest) {
-		qr.BlockID = blockHeight
-	}
+	return ViewStateWithBlockReference(types.BlockHeight(blockHeight))
 }
 
 // ViewStateWithBlockHash specifies the block hash to query the state for.
 func ViewStateWithBlockHash(blockHash string) ViewStateOption {
-	return func(qr *itypes.QueryRequest) {
-		qr.BlockID = blockHash
-	}
+	return ViewStateWithBlockReference(types.BlockHash(blockHash))
 }
 
 // ViewStateWithPrefix specifies the state key prefix to query for.
@@ -40,23 +43,62 @@ func ViewStateWithPrefix(prefix string) ViewStateOption {
 // StateOption controls the behavior when calling ViewAccount.
 type StateOption func(*itypes.QueryRequest)
 
-// StateWithFinality specifies the finality to be used when querying the account.
-func StateWithFinality(finalaity string) StateOption {
+// StateWithBlockReference specifies the block to query the account at.
+func StateWithBlockReference(ref types.BlockReference) StateOption {
 	return func(qr *itypes.QueryRequest) {
-		qr.Finality = finalaity
+		qr.Finality = string(ref.Finality())
+		qr.BlockID = ref.BlockID()
 	}
 }
 
+// StateWithFinality specifies the finality to be used when querying the account.
+func StateWithFinality(finalaity string) StateOption {
+	return StateWithBlockReference(types.BlockFinality(types.Finality(finalaity)))
+}
+
 // StateWithBlockHeight specifies the block height to query the account for.
 func StateWithBlockHeight(blockHeight int) StateOption {
-	return func(qr *itypes.QueryRequest) {
-		qr.BlockID = blockHeight
-	}
+	return StateWithBlockReference(types.BlockHeight(blockHeight))
 }
 
 // StateWithBlockHash specifies the block hash to query the account for.
 func StateWithBlockHash(blockHash string) StateOption {
+	return StateWithBlockReference(types.BlockHash(blockHash))
+}
+
+// ViewAccessKeyOption controls the behavior when calling ViewAccessKey.
+type ViewAccessKeyOption func(*itypes.QueryRequest)
+
+// ViewAccessKeyWithBlockReference specifies the block to query the access key at.
+func ViewAccessKeyWithBlockReference(ref types.BlockReference) ViewAccessKeyOption {
+	return func(qr *itypes.QueryRequest) {
+		qr.Finality = string(ref.Finality())
+		qr.BlockID = ref.BlockID()
+	}
+}
+
+// StakingOption controls the behavior when querying a staking pool.
+type StakingOption func(*itypes.QueryRequest)
+
+// StakingWithBlockReference specifies the block to query the staking pool at.
+func StakingWithBlockReference(ref types.BlockReference) StakingOption {
 	return func(qr *itypes.QueryRequest) {
-		qr.BlockID = blockHash
+		qr.Finality = string(ref.Finality())
+		qr.BlockID = ref.BlockID()
 	}
 }
+
+// StakingWithFinality specifies the finality to be used when querying the staking pool.
+func StakingWithFinality(finalaity string) StakingOption {
+	return StakingWithBlockReference(types.BlockFinality(types.Finality(finalaity)))
+}
+
+// StakingWithBlockHeight specifies the block height to query the staking pool for.
+func StakingWithBlockHeight(blockHeight int) StakingOption {
+	return StakingWithBlockReference(types.BlockHeight(blockHeight))
+}
+
+// StakingWithBlockHash specifies the block hash to query the staking pool for.
+func StakingWithBlockHash(blockHash string) StakingOption {
+	return StakingWithBlockReference(types.BlockHash(blockHash))
+}
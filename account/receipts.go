@@ -0,0 +1,244 @@
+package account
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Failure is a decoded execution failure.
+type Failure struct {
+	// Type is the failure type, such as ActionError or InvalidTxError.
+	Type string
+	// ActionIndex is the index of the failing action for an ActionError, if known.
+	ActionIndex *int
+	// Kind is the path of nested error kinds, such as [FunctionCallError ExecutionError].
+	Kind []string
+	// Message is the error message or the JSON encoded error details.
+	Message string
+	// Raw is the undecoded failure.
+	Raw map[string]interface{}
+}
+
+// String returns a single line description of the failure.
+func (f *Failure) String() string {
+	parts := append([]string{f.Type}, f.Kind...)
+	s := strings.Join(parts, ".")
+	if f.ActionIndex != nil {
+		s = fmt.Sprintf("%s (action %d)", s, *f.ActionIndex)
+	}
+	if f.Message != "" {
+		s = fmt.Sprintf("%s: %s", s, f.Message)
+	}
+	return s
+}
+
+// DecodeFailure decodes the Failure of an ExecutionStatus or FinalExecutionStatus. It returns nil
+// only if raw is nil, so a Failure without details still decodes to a Failure.
+func DecodeFailure(raw map[string]interface{}) *Failure {
+	if raw == nil {
+		return nil
+	}
+	if len(raw) == 0 {
+		return &Failure{Raw: raw, Type: "Failure"}
+	}
+	f := &Failure{Raw: raw, Type: sortedKeys(raw)[0]}
+	v := raw[f.Type]
+	if m, ok := v.(map[string]interface{}); ok && f.Type == "ActionError" {
+		if index, ok := m["index"].(float64); ok {
+			i := int(index)
+			f.ActionIndex = &i
+		}
+		v = m["kind"]
+	}
+	f.Kind, f.Message = decodeKind(v)
+	return f
+}
+
+// decodeKind walks nested single variant objects, such as {"FunctionCallError":{"ExecutionError":"msg"}},
+// returning the variant names and the innermost message or details.
+func decodeKind(v interface{}) ([]string, string) {
+	switch t := v.(type) {
+	case string:
+		if isVariantName(t) {
+			return []string{t}, ""
+		}
+		return nil, t
+	case map[string]interface{}:
+		if len(t) == 1 {
+			for k, inner := range t {
+				if isVariantName(k) {
+					kind, msg := decodeKind(inner)
+					return append([]string{k}, kind...), msg
+				}
+			}
+		}
+		bytes, err := json.Marshal(t)
+		if err != nil {
+			return nil, fmt.Sprint(t)
+		}
+		return nil, string(bytes)
+	case nil:
+		return nil, ""
+	default:
+		return nil, fmt.Sprint(t)
+	}
+}
+
+func isVariantName(s string) bool {
+	if s == "" || !unicode.IsUpper(rune(s[0])) {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// ReceiptNode is a node in the tree of outcomes produced by a transaction.
+// The root node is the transaction outcome.
+type ReceiptNode struct {
+	ID          string
+	ExecutorID  string
+	Logs        []string
+	GasBurnt    int64
+	TokensBurnt string
+	Status      ExecutionStatus
+	// StatusBasic is set instead of Status for outcomes without an ExecutionStatus, such as
+	// pending ones. Outcomes with a status that can't be decoded are ExecutionStatusBasicUnknown.
+	StatusBasic *ExecutionStatusBasic
+	Failure     *Failure
+	Children    []*ReceiptNode
+}
+
+// Failed reports whether the outcome of this node failed.
+func (n *ReceiptNode) Failed() bool {
+	return n.Failure != nil
+}
+
+// Walk calls fn for this node and all its descendants, depth first.
+func (n *ReceiptNode) Walk(fn func(node *ReceiptNode, depth int)) {
+	n.walk(fn, 0)
+}
+
+func (n *ReceiptNode) walk(fn func(node *ReceiptNode, depth int), depth int) {
+	fn(n, depth)
+	for _, c := range n.Children {
+		c.walk(fn, depth+1)
+	}
+}
+
+// Render writes a human readable representation of the tree to w.
+func (n *ReceiptNode) Render(w io.Writer) error {
+	return n.render(w, "", "")
+}
+
+// String returns the rendered tree.
+func (n *ReceiptNode) String() string {
+	var buf bytes.Buffer
+	_ = n.Render(&buf)
+	return buf.String()
+}
+
+func (n *ReceiptNode) render(w io.Writer, prefix, childPrefix string) error {
+	var status string
+	switch {
+	case n.Failure != nil:
+		status = "FAILURE " + n.Failure.String()
+	case n.StatusBasic != nil && *n.StatusBasic == ExecutionStatusBasicPending:
+		status = "PENDING"
+	case n.StatusBasic != nil:
+		status = "UNKNOWN"
+	case n.Status.SuccessReceiptID != "":
+		status = "SUCCESS receipt=" + n.Status.SuccessReceiptID
+	default:
+		status = "SUCCESS"
+	}
+	if _, err := fmt.Fprintf(w, "%s%s %s gas=%d %s\n", prefix, n.ID, n.ExecutorID, n.GasBurnt, status); err != nil {
+		return err
+	}
+	for _, l := range n.Logs {
+		if _, err := fmt.Fprintf(w, "%s  log: %s\n", childPrefix, l); err != nil {
+			return err
+		}
+	}
+	for i, c := range n.Children {
+		branch, next := "├── ", "│   "
+		if i == len(n.Children)-1 {
+			branch, next = "└── ", "    "
+		}
+		if err := c.render(w, childPrefix+branch, childPrefix+next); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReceiptTree links the transaction outcome and receipt outcomes into a tree following their receipt ids.
+// Receipt outcomes that aren't referenced by any other outcome are attached to the root.
+func (feo *FinalExecutionOutcome) ReceiptTree() *ReceiptNode {
+	outcomes := make(map[string]ExecutionOutcome, len(feo.ReceiptsOutcome))
+	for _, o := range feo.ReceiptsOutcome {
+		outcomes[o.ID] = o.Outcome
+	}
+	visited := make(map[string]bool, len(feo.ReceiptsOutcome))
+	var build func(id string, outcome ExecutionOutcome) *ReceiptNode
+	build = func(id string, outcome ExecutionOutcome) *ReceiptNode {
+		visited[id] = true
+		node := newReceiptNode(id, outcome)
+		for _, childID := range outcome.ReceiptIDs {
+			child, ok := outcomes[childID]
+			if !ok || visited[childID] {
+				continue
+			}
+			node.Children = append(node.Children, build(childID, child))
+		}
+		return node
+	}
+	root := build(feo.TransactionOutcome.ID, feo.TransactionOutcome.Outcome)
+	for _, o := range feo.ReceiptsOutcome {
+		if !visited[o.ID] {
+			root.Children = append(root.Children, build(o.ID, o.Outcome))
+		}
+	}
+	return root
+}
+
+func newReceiptNode(id string, outcome ExecutionOutcome) *ReceiptNode {
+	node := &ReceiptNode{
+		ID:          id,
+		ExecutorID:  outcome.ExecutorID,
+		Logs:        outcome.Logs,
+		GasBurnt:    outcome.GasBurnt,
+		TokensBurnt: outcome.TokensBurnt,
+	}
+	if status, ok := outcome.GetStatus(); ok {
+		node.Status = status
+		node.Failure = DecodeFailure(status.Failure)
+		return node
+	}
+	basic, ok := outcome.GetStatusBasic()
+	if !ok {
+		basic = ExecutionStatusBasicUnknown
+	}
+	node.StatusBasic = &basic
+	if basic == ExecutionStatusBasicFailure {
+		node.Failure = &Failure{Type: "Failure"}
+	}
+	return node
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,115 @@
+package account
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/textileio/near-api-go/internal/call"
+	itypes "github.com/textileio/near-api-go/internal/types"
+	"github.com/textileio/near-api-go/jsonrpc"
+	"github.com/textileio/near-api-go/util"
+)
+
+// StakedBalance queries the balance the account has staked in the provided staking pool contract.
+// The final block is queried unless opts reference another block.
+func (a *Account) StakedBalance(ctx context.Context, stakingPoolID string, opts ...StakingOption) (*big.Int, error) {
+	return a.stakingPoolBalance(ctx, stakingPoolID, "get_account_staked_balance", opts)
+}
+
+// UnstakedBalance queries the balance the account has unstaked in the provided staking pool contract.
+// The final block is queried unless opts reference another block.
+func (a *Account) UnstakedBalance(ctx context.Context, stakingPoolID string, opts ...StakingOption) (*big.Int, error) {
+	return a.stakingPoolBalance(ctx, stakingPoolID, "get_account_unstaked_balance", opts)
+}
+
+// IsUnstakedBalanceAvailable queries whether the unstaked balance of the account
+// in the provided staking pool contract can be withdrawn. The final block is queried
+// unless opts reference another block.
+func (a *Account) IsUnstakedBalanceAvailable(
+	ctx context.Context,
+	stakingPoolID string,
+	opts ...StakingOption,
+) (bool, error) {
+	var available bool
+	if err := a.viewFunction(
+		ctx,
+		stakingPoolID,
+		"is_account_unstaked_balance_available",
+		map[string]string{"account_id": a.accountID},
+		&available,
+		opts,
+	); err != nil {
+		return false, err
+	}
+	return available, nil
+}
+
+func (a *Account) stakingPoolBalance(
+	ctx context.Context,
+	stakingPoolID string,
+	methodName string,
+	opts []StakingOption,
+) (*big.Int, error) {
+	var balanceStr string
+	if err := a.viewFunction(
+		ctx,
+		stakingPoolID,
+		methodName,
+		map[string]string{"account_id": a.accountID},
+		&balanceStr,
+		opts,
+	); err != nil {
+		return nil, err
+	}
+	balance, ok := (&big.Int{}).SetString(balanceStr, 10)
+	if !ok {
+		return nil, fmt.Errorf("parsing balance %q", balanceStr)
+	}
+	return balance, nil
+}
+
+// viewFunction calls a view function on a contract at the block referenced by opts and
+// unmarshals the JSON result into result.
+func (a *Account) viewFunction(
+	ctx context.Context,
+	contractID string,
+	methodName string,
+	args interface{},
+	result interface{},
+	opts []StakingOption,
+) error {
+	argsBytes, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("marshaling args: %v", err)
+	}
+	req := &itypes.QueryRequest{
+		RequestType: "call_function",
+		AccountID:   contractID,
+		MethodName:  methodName,
+		ArgsBase64:  base64.StdEncoding.EncodeToString(argsBytes),
+		Finality:    "final",
+	}
+	for _, opt := range opts {
+		opt(req)
+	}
+	if err := itypes.CheckBlockReference(req.Finality, req.BlockID); err != nil {
+		return err
+	}
+	var res struct {
+		itypes.QueryResponse
+		Result []byte `json:"result"`
+	}
+	if err := call.Block(ctx, a.config, &res, "query", jsonrpc.NewNamedParams(req)); err != nil {
+		return fmt.Errorf("calling rpc: %v", util.MapRPCError(err))
+	}
+	if res.Error != "" {
+		return fmt.Errorf("error returned in body: %s", res.Error)
+	}
+	if err := json.Unmarshal(res.Result, result); err != nil {
+		return fmt.Errorf("unmarshaling %s result: %v", methodName, err)
+	}
+	return nil
+}
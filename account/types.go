@@ -2,6 +2,7 @@ package account
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/textileio/near-api-go/internal/types"
 )
@@ -46,6 +47,36 @@ type AccessKeyView struct {
 	FunctionCallPermissionView *FunctionCallPermissionView
 }
 
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *AccessKeyView) UnmarshalJSON(b []byte) error {
+	var res struct {
+		types.QueryResponse
+		Nonce      uint64          `json:"nonce"`
+		Permission json.RawMessage `json:"permission"`
+	}
+	if err := json.Unmarshal(b, &res); err != nil {
+		return err
+	}
+	*v = AccessKeyView{
+		QueryResponse: res.QueryResponse,
+		Nonce:         res.Nonce,
+	}
+	if len(res.Permission) == 0 || string(res.Permission) == "null" {
+		return nil
+	}
+	if string(res.Permission) == "\"FullAccess\"" {
+		v.PermissionType = FullAccessPermissionType
+		return nil
+	}
+	var view FunctionCallPermissionView
+	if err := json.Unmarshal(res.Permission, &view); err != nil {
+		return fmt.Errorf("unmarshaling permission: %v", err)
+	}
+	v.FunctionCallPermissionView = &view
+	v.PermissionType = FunctionCallPermissionType
+	return nil
+}
+
 // FunctionCall provides information about the allowed function call.
 type FunctionCall struct {
 	Allowance   string   `json:"allowance"`
@@ -97,10 +128,12 @@ const (
 
 // ExecutionOutcome is the outcome of a transaction.
 type ExecutionOutcome struct {
-	Logs       []string        `json:"logs"`
-	ReceiptIDs []string        `json:"receipt_ids"`
-	GasBurnt   int64           `json:"gas_burnt"`
-	RawStatus  json.RawMessage `json:"status"`
+	Logs        []string        `json:"logs"`
+	ReceiptIDs  []string        `json:"receipt_ids"`
+	GasBurnt    int64           `json:"gas_burnt"`
+	TokensBurnt string          `json:"tokens_burnt"`
+	ExecutorID  string          `json:"executor_id"`
+	RawStatus   json.RawMessage `json:"status"`
 }
 
 // GetStatus returns a bool indicating if the status is an ExecutionStatus, and if so, the ExecutionStatus.
@@ -147,8 +180,10 @@ func (eo *ExecutionOutcome) GetStatusBasic() (ExecutionStatusBasic, bool) {
 
 // ExecutionOutcomeWithID provides the transaction or receipt outcome with and id.
 type ExecutionOutcomeWithID struct {
-	ID      string           `json:"id"`
-	Outcome ExecutionOutcome `json:"outcome"`
+	ID string `json:"id"`
+	// BlockHash is the hash of the block where the transaction or receipt was executed.
+	BlockHash string           `json:"block_hash"`
+	Outcome   ExecutionOutcome `json:"outcome"`
 }
 
 // FinalExecutionOutcome is the final outcome of a transaction.
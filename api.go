@@ -5,10 +5,12 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"time"
 
-	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/textileio/near-api-go/account"
+	"github.com/textileio/near-api-go/internal/call"
 	itypes "github.com/textileio/near-api-go/internal/types"
+	"github.com/textileio/near-api-go/jsonrpc"
 	"github.com/textileio/near-api-go/types"
 	"github.com/textileio/near-api-go/util"
 )
@@ -19,6 +21,9 @@ type CallFunctionResponse struct {
 	Logs        []string `json:"logs"`
 	BlockHeight int      `json:"block_height"`
 	BlockHash   string   `json:"block_hash"`
+	// Error is set by nodes that report a failed function call in the response body,
+	// as {"error": "...", "result": []}, instead of as an RPC error.
+	Error string `json:"error"`
 }
 
 // ViewCodeResponse holds information about contract code.
@@ -57,15 +62,69 @@ type DataChangesResponse struct {
 
 // SyncInfo holds information about the sync status of a node.
 type SyncInfo struct {
-	LatestBlockHash   string `json:"latest_block_hash"`
-	LatestBlockHeight int    `json:"latest_block_height"`
-	// TODO: make this time.Time and use custom json conversion.
-	LatestBlockTime string `json:"latest_block_time"`
+	LatestBlockHash     string    `json:"latest_block_hash"`
+	LatestBlockHeight   int       `json:"latest_block_height"`
+	LatestStateRoot     string    `json:"latest_state_root"`
+	LatestBlockTime     time.Time `json:"latest_block_time"`
+	Syncing             bool      `json:"syncing"`
+	EarliestBlockHash   string    `json:"earliest_block_hash"`
+	EarliestBlockHeight int       `json:"earliest_block_height"`
+	EarliestBlockTime   time.Time `json:"earliest_block_time"`
+	EpochID             string    `json:"epoch_id"`
+	EpochStartHeight    int       `json:"epoch_start_height"`
+}
+
+// NodeVersion holds information about the version of a node.
+type NodeVersion struct {
+	Version      string `json:"version"`
+	Build        string `json:"build"`
+	RustcVersion string `json:"rustc_version"`
+}
+
+// ValidatorInfo holds information about a validator known to a node.
+type ValidatorInfo struct {
+	AccountID string `json:"account_id"`
+	IsSlashed bool   `json:"is_slashed"`
 }
 
 // NodeStatusResponse holds information about node status.
 type NodeStatusResponse struct {
-	SyncInfo *SyncInfo `json:"sync_info"`
+	Version               NodeVersion     `json:"version"`
+	ChainID               string          `json:"chain_id"`
+	ProtocolVersion       int             `json:"protocol_version"`
+	LatestProtocolVersion int             `json:"latest_protocol_version"`
+	RPCAddr               string          `json:"rpc_addr"`
+	GenesisHash           string          `json:"genesis_hash"`
+	Validators            []ValidatorInfo `json:"validators"`
+	SyncInfo              *SyncInfo       `json:"sync_info"`
+	ValidatorAccountID    string          `json:"validator_account_id"`
+	ValidatorPublicKey    string          `json:"validator_public_key"`
+	NodePublicKey         string          `json:"node_public_key"`
+	UptimeSec             int64           `json:"uptime_sec"`
+}
+
+// PeerInfo holds information about a peer of a node.
+type PeerInfo struct {
+	ID        string `json:"id"`
+	Addr      string `json:"addr"`
+	AccountID string `json:"account_id"`
+}
+
+// KnownProducer holds information about a block producer known to a node.
+type KnownProducer struct {
+	AccountID string `json:"account_id"`
+	Addr      string `json:"addr"`
+	PeerID    string `json:"peer_id"`
+}
+
+// NetworkInfoResponse holds information about the network connections of a node.
+type NetworkInfoResponse struct {
+	ActivePeers         []PeerInfo      `json:"active_peers"`
+	NumActivePeers      int             `json:"num_active_peers"`
+	PeerMaxCount        int             `json:"peer_max_count"`
+	SentBytesPerSec     uint64          `json:"sent_bytes_per_sec"`
+	ReceivedBytesPerSec uint64          `json:"received_bytes_per_sec"`
+	KnownProducers      []KnownProducer `json:"known_producers"`
 }
 
 // Client communicates with the NEAR API.
@@ -88,28 +147,28 @@ func (c *Client) Account(accountID string) *account.Account {
 // CallFunctionOption controls the behavior when calling CallFunction.
 type CallFunctionOption func(*itypes.QueryRequest) error
 
-// CallFunctionWithFinality specifies the finality to be used when calling the function.
-func CallFunctionWithFinality(finalaity string) CallFunctionOption {
+// CallFunctionWithBlockReference specifies the block to call the function at.
+func CallFunctionWithBlockReference(ref types.BlockReference) CallFunctionOption {
 	return func(qr *itypes.QueryRequest) error {
-		qr.Finality = finalaity
+		qr.Finality = string(ref.Finality())
+		qr.BlockID = ref.BlockID()
 		return nil
 	}
 }
 
+// CallFunctionWithFinality specifies the finality to be used when calling the function.
+func CallFunctionWithFinality(finalaity string) CallFunctionOption {
+	return CallFunctionWithBlockReference(types.BlockFinality(types.Finality(finalaity)))
+}
+
 // CallFunctionWithBlockHeight specifies the block height to call the function for.
 func CallFunctionWithBlockHeight(blockHeight int) CallFunctionOption {
-	return func(qr *itypes.QueryRequest) error {
-		qr.BlockID = blockHeight
-		return nil
-	}
+	return CallFunctionWithBlockReference(types.BlockHeight(blockHeight))
 }
 
 // CallFunctionWithBlockHash specifies the block hash to call the function for.
 func CallFunctionWithBlockHash(blockHash string) CallFunctionOption {
-	return func(qr *itypes.QueryRequest) error {
-		qr.BlockID = blockHash
-		return nil
-	}
+	return CallFunctionWithBlockReference(types.BlockHash(blockHash))
 }
 
 // CallFunctionWithArgs specified the args to call the function with.
@@ -145,18 +204,11 @@ func (c *Client) CallFunction(
 			return nil, err
 		}
 	}
-	if req.BlockID == nil && req.Finality == "" {
-		return nil, fmt.Errorf(
-			"you must provide ViewAccountWithBlockHeight, ViewAccountWithBlockHash or ViewAccountWithFinality",
-		)
-	}
-	if req.BlockID != nil && req.Finality != "" {
-		return nil, fmt.Errorf(
-			"you must provide one of ViewAccountWithBlockHeight, ViewAccountWithBlockHash or ViewAccountWithFinality",
-		)
+	if err := itypes.CheckBlockReference(req.Finality, req.BlockID); err != nil {
+		return nil, err
 	}
 	var res CallFunctionResponse
-	if err := c.config.RPCClient.CallContext(ctx, &res, "query", rpc.NewNamedParams(req)); err != nil {
+	if err := call.Block(ctx, c.config, &res, "query", jsonrpc.NewNamedParams(req)); err != nil {
 		return nil, fmt.Errorf("calling query rpc: %v", util.MapRPCError(err))
 	}
 	return &res, nil
@@ -172,25 +224,27 @@ func DataChangesWithPrefix(prefix string) DataChangesOption {
 	}
 }
 
-// DataChangesWithFinality specifies the finality to be used when querying data changes.
-func DataChangesWithFinality(finalaity string) DataChangesOption {
+// DataChangesWithBlockReference specifies the block to query data changes for.
+func DataChangesWithBlockReference(ref types.BlockReference) DataChangesOption {
 	return func(qr *itypes.ChangesRequest) {
-		qr.Finality = finalaity
+		qr.Finality = string(ref.Finality())
+		qr.BlockID = ref.BlockID()
 	}
 }
 
+// DataChangesWithFinality specifies the finality to be used when querying data changes.
+func DataChangesWithFinality(finalaity string) DataChangesOption {
+	return DataChangesWithBlockReference(types.BlockFinality(types.Finality(finalaity)))
+}
+
 // DataChangesWithBlockHeight specifies the block id to query data changes for.
 func DataChangesWithBlockHeight(blockHeight int) DataChangesOption {
-	return func(qr *itypes.ChangesRequest) {
-		qr.BlockID = blockHeight
-	}
+	return DataChangesWithBlockReference(types.BlockHeight(blockHeight))
 }
 
 // DataChangesWithBlockHash specifies the block id to query data changes for.
 func DataChangesWithBlockHash(blockHash string) DataChangesOption {
-	return func(qr *itypes.ChangesRequest) {
-		qr.BlockID = blockHash
-	}
+	return DataChangesWithBlockReference(types.BlockHash(blockHash))
 }
 
 // DataChanges queries changes to contract data changes.
@@ -206,18 +260,11 @@ func (c *Client) DataChanges(
 	for _, opt := range opts {
 		opt(req)
 	}
-	if req.BlockID == nil && req.Finality == "" {
-		return nil, fmt.Errorf(
-			"you must provide DataChangesWithBlockHeight, DataChangesWithBlockHash or DataChangesWithFinality",
-		)
-	}
-	if req.BlockID != nil && req.Finality != "" {
-		return nil, fmt.Errorf(
-			"you must provide one of DataChangesWithBlockHeight, DataChangesWithBlockHash or DataChangesWithFinality",
-		)
+	if err := itypes.CheckBlockReference(req.Finality, req.BlockID); err != nil {
+		return nil, err
 	}
 	var res DataChangesResponse
-	if err := c.config.RPCClient.CallContext(ctx, &res, "EXPERIMENTAL_changes", rpc.NewNamedParams(req)); err != nil {
+	if err := call.Block(ctx, c.config, &res, "EXPERIMENTAL_changes", jsonrpc.NewNamedParams(req)); err != nil {
 		return nil, fmt.Errorf("calling changes rpc: %v", util.MapRPCError(err))
 	}
 	return &res, nil
@@ -226,25 +273,27 @@ func (c *Client) DataChanges(
 // ViewCodeOption controls the behavior when calling ViewCode.
 type ViewCodeOption func(*itypes.QueryRequest)
 
-// ViewCodeWithFinality specifies the finality to be used when calling the function.
-func ViewCodeWithFinality(finalaity string) ViewCodeOption {
+// ViewCodeWithBlockReference specifies the block to view the code at.
+func ViewCodeWithBlockReference(ref types.BlockReference) ViewCodeOption {
 	return func(qr *itypes.QueryRequest) {
-		qr.Finality = finalaity
+		qr.Finality = string(ref.Finality())
+		qr.BlockID = ref.BlockID()
 	}
 }
 
-// ViewCodeWithBlockHeight specifies the block height to call the function for.
+// ViewCodeWithFinality specifies the finality to be used when viewing the code.
+func ViewCodeWithFinality(finalaity string) ViewCodeOption {
+	return ViewCodeWithBlockReference(types.BlockFinality(types.Finality(finalaity)))
+}
+
+// ViewCodeWithBlockHeight specifies the block height to view the code for.
 func ViewCodeWithBlockHeight(blockHeight int) ViewCodeOption {
-	return func(qr *itypes.QueryRequest) {
-		qr.BlockID = blockHeight
-	}
+	return ViewCodeWithBlockReference(types.BlockHeight(blockHeight))
 }
 
-// ViewCodeWithBlockHash specifies the block hash to call the function for.
+// ViewCodeWithBlockHash specifies the block hash to view the code for.
 func ViewCodeWithBlockHash(blockHash string) ViewCodeOption {
-	return func(qr *itypes.QueryRequest) {
-		qr.BlockID = blockHash
-	}
+	return ViewCodeWithBlockReference(types.BlockHash(blockHash))
 }
 
 // ViewCode returns the smart contract code for the provided account id.
@@ -257,18 +306,11 @@ func (c *Client) ViewCode(ctx context.Context, accountID string, opts ...ViewCod
 	for _, opt := range opts {
 		opt(req)
 	}
-	if req.BlockID == nil && req.Finality == "" {
-		return nil, fmt.Errorf(
-			"you must provide ViewAccountWithBlockHeight, ViewAccountWithBlockHash or ViewAccountWithFinality",
-		)
-	}
-	if req.BlockID != nil && req.Finality != "" {
-		return nil, fmt.Errorf(
-			"you must provide one of ViewAccountWithBlockHeight, ViewAccountWithBlockHash or ViewAccountWithFinality",
-		)
+	if err := itypes.CheckBlockReference(req.Finality, req.BlockID); err != nil {
+		return nil, err
 	}
 	var viewCodeRes ViewCodeResponse
-	if err := c.config.RPCClient.CallContext(ctx, &viewCodeRes, "query", rpc.NewNamedParams(req)); err != nil {
+	if err := call.Block(ctx, c.config, &viewCodeRes, "query", jsonrpc.NewNamedParams(req)); err != nil {
 		return nil, fmt.Errorf("calling query rpc: %v", util.MapRPCError(err))
 	}
 	return &viewCodeRes, nil
@@ -277,8 +319,30 @@ func (c *Client) ViewCode(ctx context.Context, accountID string, opts ...ViewCod
 // NodeStatus returns the node status.
 func (c *Client) NodeStatus(ctx context.Context) (*NodeStatusResponse, error) {
 	var nodeStatusRes NodeStatusResponse
-	if err := c.config.RPCClient.CallContext(ctx, &nodeStatusRes, "status"); err != nil {
+	if err := call.RPC(ctx, c.config, &nodeStatusRes, "status"); err != nil {
 		return nil, fmt.Errorf("calling status rpc: %v", util.MapRPCError(err))
 	}
 	return &nodeStatusRes, nil
 }
+
+// NetworkInfo returns information about the network connections of the node.
+func (c *Client) NetworkInfo(ctx context.Context) (*NetworkInfoResponse, error) {
+	var res NetworkInfoResponse
+	if err := call.RPC(ctx, c.config, &res, "network_info"); err != nil {
+		return nil, fmt.Errorf("calling network info rpc: %v", util.MapRPCError(err))
+	}
+	return &res, nil
+}
+
+// TransactionStatus queries the status of a transaction, including the outcomes of all its receipts.
+func (c *Client) TransactionStatus(
+	ctx context.Context,
+	txHash string,
+	senderID string,
+) (*account.FinalExecutionOutcome, error) {
+	var res account.FinalExecutionOutcome
+	if err := call.RPC(ctx, c.config, &res, "tx", txHash, senderID); err != nil {
+		return nil, fmt.Errorf("calling tx rpc: %v", util.MapRPCError(err))
+	}
+	return &res, nil
+}
@@ -2,10 +2,13 @@ package api
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
 
-	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/stretchr/testify/require"
-	"github.com/textileio/near-api-go/types"
+	"github.com/textileio/near-api-go/keys"
+	"github.com/textileio/near-api-go/neartest"
 
 	"testing"
 )
@@ -18,62 +21,190 @@ func TestIt(t *testing.T) {
 	require.NotNil(t, c)
 }
 
-// func TestViewCode(t *testing.T) {
-// 	c, cleanup := makeClient(t)
-// 	defer cleanup()
-// 	res, err := c.ViewCode(ctx, "filecoin-bridge.testnet")
-// 	require.NoError(t, err)
-// 	require.NotNil(t, res)
-// }
-
-// func TestDeployContract(t *testing.T) {
-// 	c, cleanup := makeClient(t)
-// 	defer cleanup()
-// 	res, err := c.ViewCode(ctx, "filecoin-bridge.testnet")
-// 	require.NoError(t, err)
-// 	require.NotNil(t, res)
-
-// 	bytes, err := base64.StdEncoding.DecodeString(res.CodeBase64)
-// 	require.NoError(t, err)
-// 	require.NotEmpty(t, bytes)
-
-// 	outcome, err := c.Account("<account id>").DeployContract(ctx, bytes)
-// 	require.NoError(t, err)
-// 	require.NotNil(t, outcome)
-
-// 	res2, err := c.ViewCode(ctx, "<account id>")
-// 	require.NoError(t, err)
-// 	require.NotNil(t, res)
-
-// 	require.Equal(t, res.Hash, res2.Hash)
-// }
-
-// func TestDataChanges(t *testing.T) {
-// 	c, cleanup := makeClient(t)
-// 	defer cleanup()
-// 	res, err := c.DataChanges(ctx, []string{"filecoin-bridge.testnet"}, DataChangesWithFinality("final"))
-// 	require.NoError(t, err)
-// 	require.NotNil(t, res)
-// }
+func TestReceiptUnmarshal(t *testing.T) {
+	var action Receipt
+	err := json.Unmarshal([]byte(`{
+		"predecessor_id": "alice.testnet",
+		"receiver_id": "bob.testnet",
+		"receipt_id": "r1",
+		"receipt": {"Action": {
+			"signer_id": "alice.testnet",
+			"signer_public_key": "ed25519:H9k5eiU4xXS3M4z8HzKJSLaZdqGdGwBG49o7orNC4eZW",
+			"gas_price": "103000000",
+			"output_data_receivers": [],
+			"input_data_ids": [],
+			"actions": [{"Transfer": {"deposit": "10"}}]
+		}}
+	}`), &action)
+	require.NoError(t, err)
+	require.Equal(t, "alice.testnet", action.PredecessorID)
+	require.Nil(t, action.Body.Data)
+	require.Len(t, action.Body.Action.Actions, 1)
+	require.Equal(t, "10", action.Body.Action.Actions[0].Transfer.Deposit.String())
+
+	var data Receipt
+	err = json.Unmarshal([]byte(`{
+		"predecessor_id": "alice.testnet",
+		"receiver_id": "bob.testnet",
+		"receipt_id": "r2",
+		"receipt": {"Data": {"data_id": "d1", "data": "aGk="}}
+	}`), &data)
+	require.NoError(t, err)
+	require.Nil(t, data.Body.Action)
+	require.Equal(t, "d1", data.Body.Data.DataID)
+	require.Equal(t, []byte("hi"), data.Body.Data.Data)
+}
 
-func makeClient(t *testing.T) (*Client, func()) {
-	rpcClient, err := rpc.DialContext(ctx, "https://rpc.testnet.near.org")
+func TestValidatorsUnmarshal(t *testing.T) {
+	var res ValidatorsResponse
+	err := json.Unmarshal([]byte(`{
+		"current_validators": [{"account_id": "v1.testnet", "stake": "100", "num_produced_blocks": 10}],
+		"next_validators": [{"account_id": "v2.testnet", "stake": "200"}],
+		"current_proposals": [],
+		"prev_epoch_kickout": [
+			{"account_id": "v3.testnet", "reason": {"NotEnoughBlocks": {"produced": 1, "expected": 5}}},
+			{"account_id": "v4.testnet", "reason": {"NotEnoughStake": {"stake_u128": "1", "threshold_u128": "2"}}},
+			{"account_id": "v5.testnet", "reason": "Unstaked"}
+		],
+		"epoch_start_height": 1000
+	}`), &res)
 	require.NoError(t, err)
+	require.Equal(t, 1000, res.EpochStartHeight)
+	require.Equal(t, 10, res.CurrentValidators[0].NumProducedBlocks)
+	require.Equal(t, KickoutReason{Type: "NotEnoughBlocks", Produced: 1, Expected: 5}, res.PrevEpochKickout[0].Reason)
+	require.Equal(t, KickoutReason{Type: "NotEnoughStake", Stake: "1", Threshold: "2"}, res.PrevEpochKickout[1].Reason)
+	require.Equal(t, KickoutReason{Type: "Unstaked"}, res.PrevEpochKickout[2].Reason)
+}
 
-	// keys, err := keys.NewKeyPairFromString(
-	// 	"ed25519:xxxx",
-	// )
-	// require.NoError(t, err)
+func TestProtocolConfigUnmarshal(t *testing.T) {
+	var res ProtocolConfigResponse
+	err := json.Unmarshal([]byte(`{
+		"protocol_version": 49,
+		"genesis_time": "2020-07-31T03:39:42.911378Z",
+		"chain_id": "testnet",
+		"gas_limit": 1000000000000000,
+		"runtime_config": {
+			"storage_amount_per_byte": "10000000000000000000",
+			"transaction_costs": {
+				"action_receipt_creation_config": {"send_sir": 1, "send_not_sir": 1, "execution": 1},
+				"action_creation_config": {
+					"transfer_cost": {"send_sir": 115123062500, "send_not_sir": 115123062500, "execution": 115123062500},
+					"add_key_cost": {"full_access_cost": {"send_sir": 101765125000, "send_not_sir": 101765125000, "execution": 1}}
+				},
+				"storage_usage_config": {"num_bytes_account": 100, "num_extra_bytes_record": 40},
+				"burnt_gas_reward": [3, 10]
+			},
+			"account_creation_config": {"min_allowed_top_level_account_length": 32, "registrar_account_id": "registrar"}
+		}
+	}`), &res)
+	require.NoError(t, err)
+	require.Equal(t, "testnet", res.ChainID)
+	require.Equal(t, 2020, res.GenesisTime.Year())
+	rc := res.RuntimeConfig
+	require.Equal(t, uint64(115123062500), rc.TransactionCosts.ActionCreationConfig.TransferCost.SendFee(false))
+	require.Equal(t, uint64(1), rc.TransactionCosts.ActionCreationConfig.AddKeyCost.FullAccessCost.ExecFee())
+	require.Equal(t, uint64(100), rc.TransactionCosts.StorageUsageConfig.NumBytesAccount)
+	require.Equal(t, Rational{3, 10}, rc.TransactionCosts.BurntGasReward)
+	require.Equal(t, "registrar", rc.AccountCreationConfig.RegistrarAccountID)
+
+	cost, err := rc.StorageCost(100)
+	require.NoError(t, err)
+	require.Equal(t, "1000000000000000000000", cost.String())
+}
 
-	config := &types.Config{
-		RPCClient: rpcClient,
-		// Signer:    keys,
-		NetworkID: "testnet",
-	}
-	c, err := NewClient(config)
+func TestNodeStatusUnmarshal(t *testing.T) {
+	var res NodeStatusResponse
+	err := json.Unmarshal([]byte(`{
+		"version": {"version": "1.21.0", "build": "crates-0.9.1", "rustc_version": "1.53.0"},
+		"chain_id": "testnet",
+		"protocol_version": 46,
+		"latest_protocol_version": 46,
+		"rpc_addr": "0.0.0.0:3030",
+		"validators": [{"account_id": "v1.testnet", "is_slashed": false}],
+		"sync_info": {
+			"latest_block_hash": "abc",
+			"latest_block_height": 100,
+			"latest_state_root": "def",
+			"latest_block_time": "2021-07-21T18:07:13.575930011Z",
+			"syncing": true,
+			"earliest_block_hash": "ghi",
+			"earliest_block_height": 10,
+			"earliest_block_time": "2021-07-19T18:07:13.575930011Z"
+		},
+		"validator_account_id": null,
+		"node_public_key": "ed25519:H9k5eiU4xXS3M4z8HzKJSLaZdqGdGwBG49o7orNC4eZW",
+		"uptime_sec": 42
+	}`), &res)
+	require.NoError(t, err)
+	require.Equal(t, "1.21.0", res.Version.Version)
+	require.Equal(t, "testnet", res.ChainID)
+	require.True(t, res.SyncInfo.Syncing)
+	require.Equal(t, 10, res.SyncInfo.EarliestBlockHeight)
+	require.Equal(t, 575930011, res.SyncInfo.LatestBlockTime.Nanosecond())
+	require.Equal(t, "", res.ValidatorAccountID)
+	require.Equal(t, int64(42), res.UptimeSec)
+}
+
+func TestViewCode(t *testing.T) {
+	c, cleanup := makeClient(t)
+	defer cleanup()
+	res, err := c.ViewCode(ctx, "filecoin-bridge.testnet")
+	require.NoError(t, err)
+	bytes, err := base64.StdEncoding.DecodeString(res.CodeBase64)
+	require.NoError(t, err)
+	require.Equal(t, []byte("code"), bytes)
+	require.NotEmpty(t, res.Hash)
+}
 
+func TestCallFunction(t *testing.T) {
+	c, cleanup := makeClient(t)
+	defer cleanup()
+	res, err := c.CallFunction(
+		ctx,
+		"filecoin-bridge.testnet",
+		"echo",
+		CallFunctionWithFinality("final"),
+		CallFunctionWithArgs(map[string]interface{}{"a": 1}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, `{"a":1}`, string(res.Result))
+	require.Equal(t, []string{"echo"}, res.Logs)
+}
+
+func TestDataChanges(t *testing.T) {
+	c, cleanup := makeClient(t)
+	defer cleanup()
+	outcome, err := c.Account("filecoin-bridge.testnet").FunctionCall(ctx, "filecoin-bridge.testnet", "set")
+	require.NoError(t, err)
+	require.Len(t, outcome.ReceiptsOutcome, 1)
+
+	res, err := c.DataChanges(ctx, []string{"filecoin-bridge.testnet"}, DataChangesWithFinality("final"))
+	require.NoError(t, err)
+	require.Len(t, res.Changes, 1)
+}
+
+func makeClient(t *testing.T) (*Client, func()) {
+	server := neartest.NewServer()
+	signer, err := keys.NewKeyPairFromRandom("ed25519")
+	require.NoError(t, err)
+	server.AddAccount("filecoin-bridge.testnet", big.NewInt(1000000))
+	server.AddAccessKey("filecoin-bridge.testnet", signer.GetPublicKey())
+	server.SetCode("filecoin-bridge.testnet", []byte("code"))
+	server.RegisterView("filecoin-bridge.testnet", "echo", func(v *neartest.View) ([]byte, error) {
+		v.Log("echo")
+		return v.Args, nil
+	})
+	server.RegisterCall("filecoin-bridge.testnet", "set", func(c *neartest.Call) ([]byte, error) {
+		c.Set([]byte("key"), []byte("value"))
+		return nil, nil
+	})
+
+	config, err := server.Config(signer)
+	require.NoError(t, err)
+	c, err := NewClient(config)
 	require.NoError(t, err)
 	return c, func() {
-		rpcClient.Close()
+		config.RPCClient.Close()
+		server.Close()
 	}
 }
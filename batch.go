@@ -0,0 +1,207 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/textileio/near-api-go/account"
+	"github.com/textileio/near-api-go/internal/call"
+	itypes "github.com/textileio/near-api-go/internal/types"
+	"github.com/textileio/near-api-go/jsonrpc"
+	"github.com/textileio/near-api-go/keys"
+	"github.com/textileio/near-api-go/types"
+	"github.com/textileio/near-api-go/util"
+)
+
+// BatchAccount holds the result of an account query added to a Batch.
+// It is populated by Batch.Do.
+type BatchAccount struct {
+	Result *account.AccountView
+	Err    error
+}
+
+// BatchAccessKey holds the result of an access key query added to a Batch.
+// It is populated by Batch.Do.
+type BatchAccessKey struct {
+	Result *account.AccessKeyView
+	Err    error
+}
+
+// BatchCallFunction holds the result of a function call added to a Batch.
+// It is populated by Batch.Do.
+type BatchCallFunction struct {
+	Result *CallFunctionResponse
+	Err    error
+}
+
+// BatchViewCode holds the result of a code query added to a Batch.
+// It is populated by Batch.Do.
+type BatchViewCode struct {
+	Result *ViewCodeResponse
+	Err    error
+}
+
+type batchQuery struct {
+	req    *itypes.QueryRequest
+	result interface{}
+	// done is called with the query result, or the error that occurred.
+	done func(err error)
+}
+
+// Batch collects queries at a shared block and sends them to the node in a single request.
+type Batch struct {
+	client  *Client
+	ref     types.BlockReference
+	queries []batchQuery
+	err     error
+}
+
+// NewBatch creates a Batch of queries at the referenced block.
+func (c *Client) NewBatch(ref types.BlockReference) *Batch {
+	return &Batch{client: c, ref: ref}
+}
+
+// ViewAccount adds an account query to the batch.
+func (b *Batch) ViewAccount(accountID string) *BatchAccount {
+	res := &BatchAccount{}
+	var view account.AccountView
+	b.add(&itypes.QueryRequest{
+		RequestType: "view_account",
+		AccountID:   accountID,
+	}, &view, func(err error) {
+		if err == nil {
+			err = bodyError(view.Error)
+		}
+		if err != nil {
+			res.Err = err
+			return
+		}
+		res.Result = &view
+	})
+	return res
+}
+
+// ViewAccessKey adds an access key query to the batch.
+func (b *Batch) ViewAccessKey(accountID string, pubKey *keys.PublicKey) *BatchAccessKey {
+	res := &BatchAccessKey{}
+	pubKeyStr, err := pubKey.ToString()
+	if err != nil {
+		res.Err = fmt.Errorf("converting public key to string: %v", err)
+		return res
+	}
+	var view account.AccessKeyView
+	b.add(&itypes.QueryRequest{
+		RequestType: "view_access_key",
+		AccountID:   accountID,
+		PublicKey:   pubKeyStr,
+	}, &view, func(err error) {
+		if err == nil {
+			err = bodyError(view.Error)
+		}
+		if err != nil {
+			res.Err = err
+			return
+		}
+		res.Result = &view
+	})
+	return res
+}
+
+// CallFunction adds a function call to the batch. The args should be a JSON encodable object, or nil.
+func (b *Batch) CallFunction(accountID string, methodName string, args interface{}) *BatchCallFunction {
+	res := &BatchCallFunction{}
+	if args == nil {
+		args = make(map[string]interface{})
+	}
+	bytes, err := json.Marshal(args)
+	if err != nil {
+		res.Err = fmt.Errorf("marshaling args: %v", err)
+		return res
+	}
+	var callRes CallFunctionResponse
+	b.add(&itypes.QueryRequest{
+		RequestType: "call_function",
+		AccountID:   accountID,
+		MethodName:  methodName,
+		ArgsBase64:  base64.StdEncoding.EncodeToString(bytes),
+	}, &callRes, func(err error) {
+		if err == nil {
+			err = bodyError(callRes.Error)
+		}
+		if err != nil {
+			res.Err = err
+			return
+		}
+		res.Result = &callRes
+	})
+	return res
+}
+
+// ViewCode adds a code query to the batch.
+func (b *Batch) ViewCode(accountID string) *BatchViewCode {
+	res := &BatchViewCode{}
+	var codeRes struct {
+		ViewCodeResponse
+		Error string `json:"error"`
+	}
+	b.add(&itypes.QueryRequest{
+		RequestType: "view_code",
+		AccountID:   accountID,
+	}, &codeRes, func(err error) {
+		if err == nil {
+			err = bodyError(codeRes.Error)
+		}
+		if err != nil {
+			res.Err = err
+			return
+		}
+		res.Result = &codeRes.ViewCodeResponse
+	})
+	return res
+}
+
+func (b *Batch) add(req *itypes.QueryRequest, result interface{}, done func(err error)) {
+	req.Finality = string(b.ref.Finality())
+	req.BlockID = b.ref.BlockID()
+	b.queries = append(b.queries, batchQuery{req: req, result: result, done: done})
+}
+
+// Do sends all queries in a single request and populates their results. The returned
+// error is only set if the request as a whole failed. Errors of individual queries are
+// reported in their results.
+func (b *Batch) Do(ctx context.Context) error {
+	if err := b.ref.Validate(); err != nil {
+		return err
+	}
+	if len(b.queries) == 0 {
+		return nil
+	}
+	elems := make([]jsonrpc.BatchElem, len(b.queries))
+	for i, q := range b.queries {
+		elems[i] = jsonrpc.BatchElem{
+			Method: "query",
+			Args:   []interface{}{jsonrpc.NewNamedParams(q.req)},
+			Result: q.result,
+		}
+	}
+	if err := call.Batch(ctx, b.client.config, elems); err != nil {
+		return fmt.Errorf("calling batch rpc: %v", util.MapRPCError(err))
+	}
+	for i, q := range b.queries {
+		if elems[i].Error != nil {
+			q.done(fmt.Errorf("calling query rpc: %v", util.MapRPCError(elems[i].Error)))
+			continue
+		}
+		q.done(nil)
+	}
+	return nil
+}
+
+func bodyError(msg string) error {
+	if msg == "" {
+		return nil
+	}
+	return fmt.Errorf("error returned in body: %s", msg)
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/textileio/near-api-go/account"
+	"github.com/textileio/near-api-go/jsonrpc"
+	"github.com/textileio/near-api-go/keys"
+	"github.com/textileio/near-api-go/types"
+)
+
+func TestBatch(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var reqs []struct {
+			ID     json.RawMessage        `json:"id"`
+			Method string                 `json:"method"`
+			Params map[string]interface{} `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqs))
+		var res []map[string]interface{}
+		for _, req := range reqs {
+			require.Equal(t, "query", req.Method)
+			require.Equal(t, float64(100), req.Params["block_id"])
+			msg := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+			switch req.Params["request_type"] {
+			case "view_account":
+				if req.Params["account_id"] == "missing.testnet" {
+					msg["error"] = map[string]interface{}{"code": -32000, "message": "Server error", "data": "UNKNOWN_ACCOUNT"}
+				} else {
+					msg["result"] = map[string]interface{}{"amount": "10", "block_height": 100}
+				}
+			case "view_access_key":
+				msg["result"] = map[string]interface{}{"nonce": 7, "permission": "FullAccess"}
+			case "call_function":
+				require.Equal(t, "eyJhIjoxfQ==", req.Params["args_base64"])
+				if req.Params["method_name"] == "panic" {
+					msg["result"] = map[string]interface{}{"error": "wasm execution failed", "result": []byte{}}
+				} else {
+					msg["result"] = map[string]interface{}{"result": []byte(`"hi"`)}
+				}
+			case "view_code":
+				if req.Params["account_id"] == "nocode.testnet" {
+					msg["result"] = map[string]interface{}{"error": "contract code not found"}
+				} else {
+					msg["result"] = map[string]interface{}{"hash": "h"}
+				}
+			}
+			res = append(res, msg)
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(res))
+	}))
+	defer server.Close()
+	rpcClient, err := jsonrpc.DialHTTP(server.URL)
+	require.NoError(t, err)
+	defer rpcClient.Close()
+	c, err := NewClient(&types.Config{RPCClient: rpcClient})
+	require.NoError(t, err)
+
+	pubKey, err := keys.NewPublicKeyFromString("ed25519:H9k5eiU4xXS3M4z8HzKJSLaZdqGdGwBG49o7orNC4eZW")
+	require.NoError(t, err)
+	b := c.NewBatch(types.BlockHeight(100))
+	acc := b.ViewAccount("alice.testnet")
+	missing := b.ViewAccount("missing.testnet")
+	key := b.ViewAccessKey("alice.testnet", pubKey)
+	fn := b.CallFunction("contract.testnet", "greet", map[string]int{"a": 1})
+	failedFn := b.CallFunction("contract.testnet", "panic", map[string]int{"a": 1})
+	code := b.ViewCode("contract.testnet")
+	noCode := b.ViewCode("nocode.testnet")
+	require.NoError(t, b.Do(ctx))
+	require.Equal(t, 1, requests)
+
+	require.NoError(t, acc.Err)
+	require.Equal(t, "10", acc.Result.Amount)
+	require.Error(t, missing.Err)
+	require.Contains(t, missing.Err.Error(), "UNKNOWN_ACCOUNT")
+	require.Nil(t, missing.Result)
+	require.NoError(t, key.Err)
+	require.Equal(t, uint64(7), key.Result.Nonce)
+	require.Equal(t, account.FullAccessPermissionType, key.Result.PermissionType)
+	require.NoError(t, fn.Err)
+	require.Equal(t, []byte(`"hi"`), fn.Result.Result)
+	require.NoError(t, code.Err)
+	require.Equal(t, "h", code.Result.Hash)
+	require.EqualError(t, failedFn.Err, "error returned in body: wasm execution failed")
+	require.Nil(t, failedFn.Result)
+	require.EqualError(t, noCode.Err, "error returned in body: contract code not found")
+	require.Nil(t, noCode.Result)
+
+	require.ErrorIs(t, c.NewBatch(types.BlockReference{}).Do(ctx), types.ErrNoBlockReference)
+}
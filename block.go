@@ -0,0 +1,189 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/textileio/near-api-go/internal/call"
+	itypes "github.com/textileio/near-api-go/internal/types"
+	"github.com/textileio/near-api-go/jsonrpc"
+	"github.com/textileio/near-api-go/transaction"
+	"github.com/textileio/near-api-go/types"
+	"github.com/textileio/near-api-go/util"
+)
+
+// BlockHeader holds information about a block header.
+type BlockHeader struct {
+	Height                int           `json:"height"`
+	PrevHeight            int           `json:"prev_height"`
+	EpochID               string        `json:"epoch_id"`
+	NextEpochID           string        `json:"next_epoch_id"`
+	Hash                  string        `json:"hash"`
+	PrevHash              string        `json:"prev_hash"`
+	PrevStateRoot         string        `json:"prev_state_root"`
+	ChunkReceiptsRoot     string        `json:"chunk_receipts_root"`
+	ChunkHeadersRoot      string        `json:"chunk_headers_root"`
+	ChunkTxRoot           string        `json:"chunk_tx_root"`
+	OutcomeRoot           string        `json:"outcome_root"`
+	ChunksIncluded        int           `json:"chunks_included"`
+	ChallengesRoot        string        `json:"challenges_root"`
+	Timestamp             uint64        `json:"timestamp"`
+	TimestampNanosec      string        `json:"timestamp_nanosec"`
+	RandomValue           string        `json:"random_value"`
+	ValidatorProposals    []interface{} `json:"validator_proposals"`
+	ChunkMask             []bool        `json:"chunk_mask"`
+	GasPrice              string        `json:"gas_price"`
+	RentPaid              string        `json:"rent_paid"`
+	ValidatorReward       string        `json:"validator_reward"`
+	TotalSupply           string        `json:"total_supply"`
+	ChallengesResult      []interface{} `json:"challenges_result"`
+	LastFinalBlock        string        `json:"last_final_block"`
+	LastDsFinalBlock      string        `json:"last_ds_final_block"`
+	NextBpHash            string        `json:"next_bp_hash"`
+	BlockMerkleRoot       string        `json:"block_merkle_root"`
+	Approvals             []*string     `json:"approvals"`
+	Signature             string        `json:"signature"`
+	LatestProtocolVersion int           `json:"latest_protocol_version"`
+}
+
+// ChunkHeader holds information about a chunk header.
+type ChunkHeader struct {
+	ChunkHash            string        `json:"chunk_hash"`
+	PrevBlockHash        string        `json:"prev_block_hash"`
+	OutcomeRoot          string        `json:"outcome_root"`
+	PrevStateRoot        string        `json:"prev_state_root"`
+	EncodedMerkleRoot    string        `json:"encoded_merkle_root"`
+	EncodedLength        int           `json:"encoded_length"`
+	HeightCreated        int           `json:"height_created"`
+	HeightIncluded       int           `json:"height_included"`
+	ShardID              int           `json:"shard_id"`
+	GasUsed              uint64        `json:"gas_used"`
+	GasLimit             uint64        `json:"gas_limit"`
+	RentPaid             string        `json:"rent_paid"`
+	ValidatorReward      string        `json:"validator_reward"`
+	BalanceBurnt         string        `json:"balance_burnt"`
+	OutgoingReceiptsRoot string        `json:"outgoing_receipts_root"`
+	TxRoot               string        `json:"tx_root"`
+	ValidatorProposals   []interface{} `json:"validator_proposals"`
+	Signature            string        `json:"signature"`
+}
+
+// BlockResponse holds information about a block.
+type BlockResponse struct {
+	Author string        `json:"author"`
+	Header BlockHeader   `json:"header"`
+	Chunks []ChunkHeader `json:"chunks"`
+}
+
+// ChunkTransaction holds information about a transaction included in a chunk.
+type ChunkTransaction struct {
+	SignerID   string                   `json:"signer_id"`
+	PublicKey  string                   `json:"public_key"`
+	Nonce      uint64                   `json:"nonce"`
+	ReceiverID string                   `json:"receiver_id"`
+	Actions    []transaction.ActionView `json:"actions"`
+	Signature  string                   `json:"signature"`
+	Hash       string                   `json:"hash"`
+}
+
+// ChunkResponse holds information about a chunk.
+type ChunkResponse struct {
+	Author       string             `json:"author"`
+	Header       ChunkHeader        `json:"header"`
+	Transactions []ChunkTransaction `json:"transactions"`
+	Receipts     []Receipt          `json:"receipts"`
+}
+
+// BlockOption controls the behavior when calling Block.
+type BlockOption func(*itypes.BlockRequest)
+
+// BlockWithBlockReference specifies the block to query.
+func BlockWithBlockReference(ref types.BlockReference) BlockOption {
+	return func(br *itypes.BlockRequest) {
+		br.Finality = string(ref.Finality())
+		br.BlockID = ref.BlockID()
+	}
+}
+
+// BlockWithFinality specifies the finality to be used when querying the block.
+func BlockWithFinality(finalaity string) BlockOption {
+	return BlockWithBlockReference(types.BlockFinality(types.Finality(finalaity)))
+}
+
+// BlockWithBlockHeight specifies the block height to query.
+func BlockWithBlockHeight(blockHeight int) BlockOption {
+	return BlockWithBlockReference(types.BlockHeight(blockHeight))
+}
+
+// BlockWithBlockHash specifies the block hash to query.
+func BlockWithBlockHash(blockHash string) BlockOption {
+	return BlockWithBlockReference(types.BlockHash(blockHash))
+}
+
+// Block queries information about a block.
+func (c *Client) Block(ctx context.Context, opts ...BlockOption) (*BlockResponse, error) {
+	req := &itypes.BlockRequest{}
+	for _, opt := range opts {
+		opt(req)
+	}
+	if err := itypes.CheckBlockReference(req.Finality, req.BlockID); err != nil {
+		return nil, err
+	}
+	var res BlockResponse
+	if err := call.Block(ctx, c.config, &res, "block", jsonrpc.NewNamedParams(req)); err != nil {
+		return nil, fmt.Errorf("calling block rpc: %v", util.MapRPCError(err))
+	}
+	return &res, nil
+}
+
+// ChunkOption controls the behavior when calling Chunk.
+type ChunkOption func(*itypes.ChunkRequest)
+
+// ChunkWithChunkID specifies the hash of the chunk to query.
+func ChunkWithChunkID(chunkID string) ChunkOption {
+	return func(cr *itypes.ChunkRequest) {
+		cr.ChunkID = chunkID
+	}
+}
+
+// ChunkWithBlockReference specifies the block and shard id of the chunk to query.
+// The block must be referenced by height or hash.
+func ChunkWithBlockReference(ref types.BlockReference, shardID int) ChunkOption {
+	return func(cr *itypes.ChunkRequest) {
+		cr.Finality = string(ref.Finality())
+		cr.BlockID = ref.BlockID()
+		cr.ShardID = &shardID
+	}
+}
+
+// ChunkWithBlockHeight specifies the block height and shard id of the chunk to query.
+func ChunkWithBlockHeight(blockHeight int, shardID int) ChunkOption {
+	return ChunkWithBlockReference(types.BlockHeight(blockHeight), shardID)
+}
+
+// ChunkWithBlockHash specifies the block hash and shard id of the chunk to query.
+func ChunkWithBlockHash(blockHash string, shardID int) ChunkOption {
+	return ChunkWithBlockReference(types.BlockHash(blockHash), shardID)
+}
+
+// Chunk queries information about a chunk.
+func (c *Client) Chunk(ctx context.Context, opts ...ChunkOption) (*ChunkResponse, error) {
+	req := &itypes.ChunkRequest{}
+	for _, opt := range opts {
+		opt(req)
+	}
+	if req.Finality != "" {
+		return nil, fmt.Errorf("chunks can't be queried by finality, provide a block height or block hash")
+	}
+	if req.ChunkID == "" && req.BlockID == nil {
+		return nil, fmt.Errorf("you must provide ChunkWithChunkID, ChunkWithBlockHeight or ChunkWithBlockHash")
+	}
+	if req.ChunkID != "" && req.BlockID != nil {
+		return nil, fmt.Errorf("you must provide one of ChunkWithChunkID, ChunkWithBlockHeight or ChunkWithBlockHash")
+	}
+	var res ChunkResponse
+	if err := call.Block(ctx, c.config, &res, "chunk", jsonrpc.NewNamedParams(req)); err != nil {
+		return nil, fmt.Errorf("calling chunk rpc: %v", util.MapRPCError(err))
+	}
+	return &res, nil
+}
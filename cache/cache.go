@@ -0,0 +1,161 @@
+package cache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Store is a bounded key-value store for RPC responses.
+type Store interface {
+	// Get returns the value for the key, if present.
+	Get(key string) ([]byte, bool)
+	// Add sets the value for the key, possibly evicting other entries.
+	Add(key string, value []byte)
+}
+
+// Stats holds the number of cache hits and misses.
+type Stats struct {
+	Hits            uint64
+	Misses          uint64
+	AccessKeyHits   uint64
+	AccessKeyMisses uint64
+}
+
+type config struct {
+	store        Store
+	size         int
+	accessKeyTTL time.Duration
+}
+
+// Option controls the behavior of a Cache.
+type Option func(*config)
+
+// WithStore specifies the Store holding responses. An LRU is used by default.
+func WithStore(store Store) Option {
+	return func(c *config) {
+		c.store = store
+	}
+}
+
+// WithSize specifies the number of responses held by the default LRU store.
+func WithSize(size int) Option {
+	return func(c *config) {
+		c.size = size
+	}
+}
+
+// WithAccessKeyTTL specifies how long access keys are cached. Access keys aren't cached if zero.
+func WithAccessKeyTTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.accessKeyTTL = ttl
+	}
+}
+
+// Cache caches responses of RPC requests referencing immutable blocks,
+// and access keys for a short time.
+type Cache struct {
+	store      Store
+	accessKeys *TTL
+
+	finalHeight     int64
+	hits            uint64
+	misses          uint64
+	accessKeyHits   uint64
+	accessKeyMisses uint64
+}
+
+// New creates a Cache.
+func New(opts ...Option) *Cache {
+	cfg := config{
+		size:         1000,
+		accessKeyTTL: 10 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	c := &Cache{store: cfg.store}
+	if c.store == nil {
+		c.store = NewLRU(cfg.size)
+	}
+	if cfg.accessKeyTTL > 0 {
+		c.accessKeys = NewTTL(cfg.accessKeyTTL)
+	}
+	return c
+}
+
+// Get returns the cached response for the key.
+func (c *Cache) Get(key string) ([]byte, bool) {
+	value, ok := c.store.Get(key)
+	if ok {
+		atomic.AddUint64(&c.hits, 1)
+	} else {
+		atomic.AddUint64(&c.misses, 1)
+	}
+	return value, ok
+}
+
+// Add caches the response for the key.
+func (c *Cache) Add(key string, value []byte) {
+	c.store.Add(key, value)
+}
+
+// ObserveFinalHeight records the height of a final block. Blocks at or below
+// the highest final height observed can't change.
+func (c *Cache) ObserveFinalHeight(height int) {
+	for {
+		current := atomic.LoadInt64(&c.finalHeight)
+		if int64(height) <= current || atomic.CompareAndSwapInt64(&c.finalHeight, current, int64(height)) {
+			return
+		}
+	}
+}
+
+// FinalHeight returns the highest final height observed.
+func (c *Cache) FinalHeight() int {
+	return int(atomic.LoadInt64(&c.finalHeight))
+}
+
+// AccessKey returns the cached access key for the key.
+func (c *Cache) AccessKey(key string) (interface{}, bool) {
+	if c.accessKeys == nil {
+		return nil, false
+	}
+	value, ok := c.accessKeys.Get(key)
+	if ok {
+		atomic.AddUint64(&c.accessKeyHits, 1)
+	} else {
+		atomic.AddUint64(&c.accessKeyMisses, 1)
+	}
+	return value, ok
+}
+
+// SetAccessKey caches the access key for the key.
+func (c *Cache) SetAccessKey(key string, value interface{}) {
+	if c.accessKeys != nil {
+		c.accessKeys.Set(key, value)
+	}
+}
+
+// UpdateAccessKey replaces the cached access key for the key, i.e. after its nonce was used.
+func (c *Cache) UpdateAccessKey(key string, value interface{}) {
+	if c.accessKeys != nil {
+		c.accessKeys.Update(key, value)
+	}
+}
+
+// InvalidateAccessKey removes the cached access key for the key.
+func (c *Cache) InvalidateAccessKey(key string) {
+	if c.accessKeys != nil {
+		c.accessKeys.Delete(key)
+	}
+}
+
+// Stats returns the number of hits and misses.
+func (c *Cache) Stats() Stats {
+	return Stats{
+		Hits:            atomic.LoadUint64(&c.hits),
+		Misses:          atomic.LoadUint64(&c.misses),
+		AccessKeyHits:   atomic.LoadUint64(&c.accessKeyHits),
+		AccessKeyMisses: atomic.LoadUint64(&c.accessKeyMisses),
+	}
+}
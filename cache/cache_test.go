@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU(t *testing.T) {
+	l := NewLRU(2)
+	l.Add("a", []byte("1"))
+	l.Add("b", []byte("2"))
+	_, ok := l.Get("a")
+	require.True(t, ok)
+	l.Add("c", []byte("3"))
+	require.Equal(t, 2, l.Len())
+
+	_, ok = l.Get("b")
+	require.False(t, ok)
+	v, ok := l.Get("a")
+	require.True(t, ok)
+	require.Equal(t, []byte("1"), v)
+	_, ok = l.Get("c")
+	require.True(t, ok)
+}
+
+func TestTTL(t *testing.T) {
+	ttl := NewTTL(20 * time.Millisecond)
+	ttl.Update("a", 1)
+	_, ok := ttl.Get("a")
+	require.False(t, ok)
+
+	ttl.Set("a", 1)
+	ttl.Update("a", 2)
+	v, ok := ttl.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 2, v)
+
+	time.Sleep(30 * time.Millisecond)
+	_, ok = ttl.Get("a")
+	require.False(t, ok)
+
+	ttl.Set("b", 1)
+	ttl.Delete("b")
+	_, ok = ttl.Get("b")
+	require.False(t, ok)
+}
+
+func TestCache(t *testing.T) {
+	c := New(WithSize(10))
+	_, ok := c.Get("a")
+	require.False(t, ok)
+	c.Add("a", []byte("1"))
+	_, ok = c.Get("a")
+	require.True(t, ok)
+
+	_, ok = c.AccessKey("k")
+	require.False(t, ok)
+	c.SetAccessKey("k", 1)
+	_, ok = c.AccessKey("k")
+	require.True(t, ok)
+	c.InvalidateAccessKey("k")
+	_, ok = c.AccessKey("k")
+	require.False(t, ok)
+
+	require.Equal(t, Stats{Hits: 1, Misses: 1, AccessKeyHits: 1, AccessKeyMisses: 2}, c.Stats())
+
+	c.ObserveFinalHeight(10)
+	c.ObserveFinalHeight(5)
+	require.Equal(t, 10, c.FinalHeight())
+
+	c = New(WithAccessKeyTTL(0))
+	c.SetAccessKey("k", 1)
+	_, ok = c.AccessKey("k")
+	require.False(t, ok)
+}
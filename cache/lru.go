@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// LRU is a Store holding a bounded number of entries, evicting the least recently used entry first.
+type LRU struct {
+	size int
+
+	lk      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type lruEntry struct {
+	key   string
+	value []byte
+}
+
+// NewLRU creates an LRU holding up to size entries.
+func NewLRU(size int) *LRU {
+	if size < 1 {
+		size = 1
+	}
+	return &LRU{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get implements Store.
+func (l *LRU) Get(key string) ([]byte, bool) {
+	l.lk.Lock()
+	defer l.lk.Unlock()
+	e, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+	l.order.MoveToFront(e)
+	return e.Value.(*lruEntry).value, true
+}
+
+// Add implements Store.
+func (l *LRU) Add(key string, value []byte) {
+	l.lk.Lock()
+	defer l.lk.Unlock()
+	if e, ok := l.entries[key]; ok {
+		e.Value.(*lruEntry).value = value
+		l.order.MoveToFront(e)
+		return
+	}
+	l.entries[key] = l.order.PushFront(&lruEntry{key: key, value: value})
+	for l.order.Len() > l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Len returns the number of entries.
+func (l *LRU) Len() int {
+	l.lk.Lock()
+	defer l.lk.Unlock()
+	return l.order.Len()
+}
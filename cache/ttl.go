@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// TTL holds entries that expire after a fixed duration.
+type TTL struct {
+	ttl time.Duration
+
+	lk      sync.Mutex
+	entries map[string]ttlEntry
+}
+
+type ttlEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// NewTTL creates a TTL whose entries expire after the provided duration.
+func NewTTL(ttl time.Duration) *TTL {
+	return &TTL{
+		ttl:     ttl,
+		entries: make(map[string]ttlEntry),
+	}
+}
+
+// Get returns the value for the key if it hasn't expired.
+func (t *TTL) Get(key string) (interface{}, bool) {
+	t.lk.Lock()
+	defer t.lk.Unlock()
+	e, ok := t.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expires) {
+		delete(t.entries, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Set sets the value for the key, resetting its expiration.
+func (t *TTL) Set(key string, value interface{}) {
+	t.lk.Lock()
+	defer t.lk.Unlock()
+	now := time.Now()
+	for k, e := range t.entries {
+		if now.After(e.expires) {
+			delete(t.entries, k)
+		}
+	}
+	t.entries[key] = ttlEntry{value: value, expires: now.Add(t.ttl)}
+}
+
+// Update replaces the value for the key without changing its expiration.
+// It does nothing if the key isn't set or has expired.
+func (t *TTL) Update(key string, value interface{}) {
+	t.lk.Lock()
+	defer t.lk.Unlock()
+	e, ok := t.entries[key]
+	if !ok || time.Now().After(e.expires) {
+		return
+	}
+	e.value = value
+	t.entries[key] = e
+}
+
+// Delete removes the key.
+func (t *TTL) Delete(key string) {
+	t.lk.Lock()
+	defer t.lk.Unlock()
+	delete(t.entries, key)
+}
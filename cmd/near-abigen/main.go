@@ -0,0 +1,53 @@
+// Command near-abigen generates a typed Go client from the ABI file of a contract built
+// with near-sdk-rs.
+//
+//	near-abigen -abi counter_abi.json -pkg counter -out counter.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/textileio/near-api-go/abigen"
+)
+
+func main() {
+	abiPath := flag.String("abi", "", "path of the ABI file")
+	pkg := flag.String("pkg", "", "package name of the generated code")
+	typeName := flag.String("type", "", "name of the generated client type, defaults to the contract name")
+	out := flag.String("out", "", "path of the generated file, defaults to stdout")
+	flag.Parse()
+
+	if err := run(*abiPath, *pkg, *typeName, *out); err != nil {
+		fmt.Fprintf(os.Stderr, "near-abigen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(abiPath, pkg, typeName, out string) error {
+	if abiPath == "" || pkg == "" {
+		return fmt.Errorf("-abi and -pkg are required")
+	}
+	abi, err := abigen.Load(abiPath)
+	if err != nil {
+		return err
+	}
+	var opts []abigen.Option
+	if typeName != "" {
+		opts = append(opts, abigen.WithTypeName(typeName))
+	}
+	src, err := abigen.Generate(abi, pkg, opts...)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	if err := ioutil.WriteFile(out, src, 0644); err != nil {
+		return fmt.Errorf("writing generated file: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// Package contract provides typed access to the view and change methods of a contract.
+package contract
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	api "github.com/textileio/near-api-go"
+	"github.com/textileio/near-api-go/account"
+	"github.com/textileio/near-api-go/transaction"
+	"github.com/textileio/near-api-go/types"
+)
+
+// Contract is a contract bound to the Viewer used for its view methods, and the account
+// signing calls to its change methods.
+type Contract struct {
+	contractID string
+	viewer     api.Viewer
+	account    account.Sender
+}
+
+// New creates a Contract for contractID. The sender may be nil if only view methods are used.
+func New(contractID string, viewer api.Viewer, sender account.Sender) *Contract {
+	return &Contract{
+		contractID: contractID,
+		viewer:     viewer,
+		account:    sender,
+	}
+}
+
+// ID returns the id of the contract account.
+func (c *Contract) ID() string {
+	return c.contractID
+}
+
+// View calls the view method with the JSON encoded args, which may be nil, and decodes its JSON
+// result into a T. The method is called at the optimistic finality unless opts reference a block.
+func View[T any](
+	ctx context.Context,
+	c *Contract,
+	method string,
+	args interface{},
+	opts ...api.CallFunctionOption,
+) (T, error) {
+	var result T
+	if c.viewer == nil {
+		return result, fmt.Errorf("no viewer configured")
+	}
+	opts = append([]api.CallFunctionOption{
+		api.CallFunctionWithArgs(args),
+		api.CallFunctionWithBlockReference(types.Optimistic()),
+	}, opts...)
+	res, err := c.viewer.CallFunction(ctx, c.contractID, method, opts...)
+	if err != nil {
+		return result, fmt.Errorf("calling function: %v", err)
+	}
+	if res.Error != "" {
+		return result, fmt.Errorf("calling function: error returned in body: %s", res.Error)
+	}
+	if err := decode(res.Result, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// Call signs and sends a call of the change method with the JSON encoded args, which may be nil,
+// and decodes the JSON return value of the transaction into a T. The default gas is used if gas is
+// zero, and no deposit is attached if deposit is nil.
+func Call[T any](
+	ctx context.Context,
+	c *Contract,
+	method string,
+	args interface{},
+	gas uint64,
+	deposit *big.Int,
+) (T, error) {
+	var result T
+	if c.account == nil {
+		return result, fmt.Errorf("no account configured")
+	}
+	var opts []transaction.FunctionCallOpton
+	if args != nil {
+		opts = append(opts, transaction.FunctionCallWithArgs(args))
+	}
+	if gas != 0 {
+		opts = append(opts, transaction.FunctionCallWithGas(gas))
+	}
+	if deposit != nil {
+		opts = append(opts, transaction.FunctionCallWithDeposit(*deposit))
+	}
+	outcome, err := c.account.FunctionCall(ctx, c.contractID, method, opts...)
+	if err != nil {
+		return result, fmt.Errorf("calling function: %v", err)
+	}
+	status, ok := outcome.GetStatus()
+	if !ok {
+		return result, fmt.Errorf("no status in transaction outcome")
+	}
+	value, err := base64.StdEncoding.DecodeString(status.SuccessValue)
+	if err != nil {
+		return result, fmt.Errorf("decoding success value: %v", err)
+	}
+	if err := decode(value, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// decode unmarshals a JSON result, leaving v untouched if the method returned nothing.
+func decode(data []byte, v interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("unmarshaling result: %v", err)
+	}
+	return nil
+}
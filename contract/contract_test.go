@@ -0,0 +1,107 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	api "github.com/textileio/near-api-go"
+	"github.com/textileio/near-api-go/account"
+	"github.com/textileio/near-api-go/keys"
+	"github.com/textileio/near-api-go/mocks"
+	"github.com/textileio/near-api-go/neartest"
+)
+
+var ctx = context.Background()
+
+type greeting struct {
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+func TestContract(t *testing.T) {
+	server := neartest.NewServer()
+	defer server.Close()
+	signer, err := keys.NewKeyPairFromRandom("ed25519")
+	require.NoError(t, err)
+	server.AddAccount("alice.test", big.NewInt(1000))
+	server.AddAccessKey("alice.test", signer.GetPublicKey())
+	server.AddAccount("counter.test", new(big.Int))
+
+	count := func(get func([]byte) ([]byte, bool)) int {
+		v, _ := get([]byte("count"))
+		n, _ := strconv.Atoi(string(v))
+		return n
+	}
+	server.RegisterView("counter.test", "greet", func(v *neartest.View) ([]byte, error) {
+		var args struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(v.Args, &args); err != nil {
+			return nil, err
+		}
+		return json.Marshal(greeting{Message: "hello " + args.Name, Count: count(v.Get)})
+	})
+	server.RegisterCall("counter.test", "increment", func(c *neartest.Call) ([]byte, error) {
+		var args struct {
+			By int `json:"by"`
+		}
+		if err := json.Unmarshal(c.Args, &args); err != nil {
+			return nil, err
+		}
+		n := count(c.Get) + args.By + int(c.Deposit.Int64())
+		c.Set([]byte("count"), []byte(strconv.Itoa(n)))
+		return json.Marshal(n)
+	})
+	server.RegisterCall("counter.test", "reset", func(c *neartest.Call) ([]byte, error) {
+		c.Delete([]byte("count"))
+		return nil, nil
+	})
+
+	config, err := server.Config(signer)
+	require.NoError(t, err)
+	defer config.RPCClient.Close()
+	client, err := api.NewClient(config)
+	require.NoError(t, err)
+	c := New("counter.test", client, account.NewAccount(config, "alice.test"))
+	require.Equal(t, "counter.test", c.ID())
+
+	n, err := Call[int](ctx, c, "increment", map[string]int{"by": 2}, 0, big.NewInt(3))
+	require.NoError(t, err)
+	require.Equal(t, 5, n)
+	require.Equal(t, big.NewInt(997), server.Balance("alice.test"))
+
+	g, err := View[greeting](ctx, c, "greet", map[string]string{"name": "bob"})
+	require.NoError(t, err)
+	require.Equal(t, greeting{Message: "hello bob", Count: 5}, g)
+
+	_, err = Call[struct{}](ctx, c, "reset", nil, 30000000000000, nil)
+	require.NoError(t, err)
+	g, err = View[greeting](ctx, c, "greet", map[string]string{"name": "bob"}, api.CallFunctionWithFinality("final"))
+	require.NoError(t, err)
+	require.Equal(t, 0, g.Count)
+
+	_, err = View[int](ctx, c, "greet", map[string]string{"name": "bob"})
+	require.Error(t, err)
+
+	_, err = Call[int](ctx, New("counter.test", client, nil), "increment", nil, 0, nil)
+	require.EqualError(t, err, "no account configured")
+}
+
+func TestViewBodyError(t *testing.T) {
+	viewer := &mocks.Viewer{
+		CallFunctionFunc: func(
+			ctx context.Context,
+			accountID string,
+			methodName string,
+			opts ...api.CallFunctionOption,
+		) (*api.CallFunctionResponse, error) {
+			return &api.CallFunctionResponse{Error: "wasm execution failed", Result: []byte{}}, nil
+		},
+	}
+	_, err := View[greeting](ctx, New("counter.test", viewer, nil), "greet", nil)
+	require.EqualError(t, err, "calling function: error returned in body: wasm execution failed")
+}
@@ -0,0 +1,97 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	logging "github.com/textileio/go-log/v2"
+	"github.com/textileio/near-api-go/account"
+)
+
+const logPrefix = "EVENT_JSON:"
+
+var (
+	log = logging.Logger("nearclient/events")
+
+	// ErrNotEvent is returned from ParseLog when the log line isn't a NEP-297 event.
+	ErrNotEvent = errors.New("log is not an event")
+)
+
+// Event is a NEP-297 event emitted by a contract.
+type Event struct {
+	Standard string          `json:"standard"`
+	Version  string          `json:"version"`
+	Event    string          `json:"event"`
+	Data     json.RawMessage `json:"data,omitempty"`
+
+	// Contract is the account that emitted the event. It is empty if the event was parsed from bare logs.
+	Contract string `json:"-"`
+	// OutcomeID is the id of the transaction or receipt that emitted the event. It is empty if the event
+	// was parsed from bare logs.
+	OutcomeID string `json:"-"`
+}
+
+// DecodeData unmarshals the event data into v.
+func (e Event) DecodeData(v interface{}) error {
+	if len(e.Data) == 0 {
+		return fmt.Errorf("event has no data")
+	}
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return fmt.Errorf("unmarshaling event data: %v", err)
+	}
+	return nil
+}
+
+// ParseLog parses a single log line as a NEP-297 event.
+// ErrNotEvent is returned if the line doesn't start with the EVENT_JSON: prefix.
+func ParseLog(line string) (*Event, error) {
+	if !strings.HasPrefix(line, logPrefix) {
+		return nil, ErrNotEvent
+	}
+	var e Event
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(line, logPrefix)), &e); err != nil {
+		return nil, fmt.Errorf("unmarshaling event: %v", err)
+	}
+	if e.Standard == "" || e.Version == "" || e.Event == "" {
+		return nil, fmt.Errorf("event must have standard, version and event fields")
+	}
+	return &e, nil
+}
+
+// FromLogs extracts all events from the provided logs, such as those of an
+// ExecutionOutcome or a CallFunctionResponse. Malformed events are skipped.
+func FromLogs(logs []string) []Event {
+	var res []Event
+	for _, line := range logs {
+		e, err := ParseLog(line)
+		if err == ErrNotEvent {
+			continue
+		}
+		if err != nil {
+			log.Warnf("skipping malformed event log %q: %v", line, err)
+			continue
+		}
+		res = append(res, *e)
+	}
+	return res
+}
+
+// FromOutcome extracts all events emitted by the transaction and its receipts, in execution order.
+func FromOutcome(outcome *account.FinalExecutionOutcome) []Event {
+	res := fromOutcomeWithID(outcome.TransactionOutcome)
+	for _, o := range outcome.ReceiptsOutcome {
+		res = append(res, fromOutcomeWithID(o)...)
+	}
+	return res
+}
+
+func fromOutcomeWithID(o account.ExecutionOutcomeWithID) []Event {
+	events := FromLogs(o.Outcome.Logs)
+	for i := range events {
+		events[i].Contract = o.Outcome.ExecutorID
+		events[i].OutcomeID = o.ID
+	}
+	return events
+}
@@ -0,0 +1,87 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/textileio/near-api-go/account"
+)
+
+func TestParseLog(t *testing.T) {
+	e, err := ParseLog(
+		`EVENT_JSON:{"standard":"nep141","version":"1.0.0","event":"ft_mint","data":[{"owner_id":"a.near","amount":"10"}]}`,
+	)
+	require.NoError(t, err)
+	require.Equal(t, "nep141", e.Standard)
+	require.Equal(t, "1.0.0", e.Version)
+	require.Equal(t, "ft_mint", e.Event)
+
+	_, err = ParseLog("hello world")
+	require.Equal(t, ErrNotEvent, err)
+
+	_, err = ParseLog(`EVENT_JSON:{"standard":"nep141"}`)
+	require.Error(t, err)
+
+	_, err = ParseLog(`EVENT_JSON:{not json`)
+	require.Error(t, err)
+}
+
+func TestFromLogs(t *testing.T) {
+	events := FromLogs([]string{
+		"plain log",
+		`EVENT_JSON:{"standard":"nep171","version":"1.0.0","event":"nft_burn","data":[]}`,
+		`EVENT_JSON:malformed`,
+		`EVENT_JSON:{"standard":"nep141","version":"1.0.0","event":"ft_burn","data":[]}`,
+	})
+	require.Len(t, events, 2)
+	require.Equal(t, "nft_burn", events[0].Event)
+	require.Equal(t, "ft_burn", events[1].Event)
+}
+
+func TestFromOutcome(t *testing.T) {
+	outcome := &account.FinalExecutionOutcome{
+		TransactionOutcome: account.ExecutionOutcomeWithID{
+			ID:      "tx",
+			Outcome: account.ExecutionOutcome{ExecutorID: "alice.near"},
+		},
+		ReceiptsOutcome: []account.ExecutionOutcomeWithID{
+			{
+				ID: "r1",
+				Outcome: account.ExecutionOutcome{
+					ExecutorID: "token.near",
+					Logs: []string{
+						`EVENT_JSON:{"standard":"nep141","version":"1.0.0","event":"ft_transfer","data":[` +
+							`{"old_owner_id":"alice.near","new_owner_id":"bob.near","amount":"5","memo":"hi"}]}`,
+					},
+				},
+			},
+			{
+				ID: "r2",
+				Outcome: account.ExecutionOutcome{
+					ExecutorID: "nft.near",
+					Logs: []string{
+						`EVENT_JSON:{"standard":"nep171","version":"1.0.0","event":"nft_transfer","data":[` +
+							`{"old_owner_id":"alice.near","new_owner_id":"bob.near","token_ids":["1","2"]}]}`,
+					},
+				},
+			},
+		},
+	}
+	events := FromOutcome(outcome)
+	require.Len(t, events, 2)
+	require.Equal(t, "token.near", events[0].Contract)
+	require.Equal(t, "r1", events[0].OutcomeID)
+	require.Equal(t, "nft.near", events[1].Contract)
+
+	ft, err := DecodeFtTransfer(events[0])
+	require.NoError(t, err)
+	require.Equal(t, []FtTransfer{{OldOwnerID: "alice.near", NewOwnerID: "bob.near", Amount: "5", Memo: "hi"}}, ft)
+
+	_, err = DecodeNftTransfer(events[0])
+	require.Error(t, err)
+
+	nft, err := DecodeNftTransfer(events[1])
+	require.NoError(t, err)
+	require.Len(t, nft, 1)
+	require.Equal(t, []string{"1", "2"}, nft[0].TokenIDs)
+}
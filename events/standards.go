@@ -0,0 +1,117 @@
+package events
+
+import "fmt"
+
+const (
+	// StandardNEP141 is the standard name of fungible token events.
+	StandardNEP141 = "nep141"
+	// StandardNEP171 is the standard name of non-fungible token events.
+	StandardNEP171 = "nep171"
+)
+
+// FtMint is the data of a NEP-141 ft_mint event.
+type FtMint struct {
+	OwnerID string `json:"owner_id"`
+	Amount  string `json:"amount"`
+	Memo    string `json:"memo,omitempty"`
+}
+
+// FtBurn is the data of a NEP-141 ft_burn event.
+type FtBurn struct {
+	OwnerID string `json:"owner_id"`
+	Amount  string `json:"amount"`
+	Memo    string `json:"memo,omitempty"`
+}
+
+// FtTransfer is the data of a NEP-141 ft_transfer event.
+type FtTransfer struct {
+	OldOwnerID string `json:"old_owner_id"`
+	NewOwnerID string `json:"new_owner_id"`
+	Amount     string `json:"amount"`
+	Memo       string `json:"memo,omitempty"`
+}
+
+// NftMint is the data of a NEP-171 nft_mint event.
+type NftMint struct {
+	OwnerID  string   `json:"owner_id"`
+	TokenIDs []string `json:"token_ids"`
+	Memo     string   `json:"memo,omitempty"`
+}
+
+// NftBurn is the data of a NEP-171 nft_burn event.
+type NftBurn struct {
+	OwnerID      string   `json:"owner_id"`
+	AuthorizedID string   `json:"authorized_id,omitempty"`
+	TokenIDs     []string `json:"token_ids"`
+	Memo         string   `json:"memo,omitempty"`
+}
+
+// NftTransfer is the data of a NEP-171 nft_transfer event.
+type NftTransfer struct {
+	AuthorizedID string   `json:"authorized_id,omitempty"`
+	OldOwnerID   string   `json:"old_owner_id"`
+	NewOwnerID   string   `json:"new_owner_id"`
+	TokenIDs     []string `json:"token_ids"`
+	Memo         string   `json:"memo,omitempty"`
+}
+
+// DecodeFtMint decodes the data of a NEP-141 ft_mint event.
+func DecodeFtMint(e Event) ([]FtMint, error) {
+	var res []FtMint
+	if err := decode(e, StandardNEP141, "ft_mint", &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DecodeFtBurn decodes the data of a NEP-141 ft_burn event.
+func DecodeFtBurn(e Event) ([]FtBurn, error) {
+	var res []FtBurn
+	if err := decode(e, StandardNEP141, "ft_burn", &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DecodeFtTransfer decodes the data of a NEP-141 ft_transfer event.
+func DecodeFtTransfer(e Event) ([]FtTransfer, error) {
+	var res []FtTransfer
+	if err := decode(e, StandardNEP141, "ft_transfer", &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DecodeNftMint decodes the data of a NEP-171 nft_mint event.
+func DecodeNftMint(e Event) ([]NftMint, error) {
+	var res []NftMint
+	if err := decode(e, StandardNEP171, "nft_mint", &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DecodeNftBurn decodes the data of a NEP-171 nft_burn event.
+func DecodeNftBurn(e Event) ([]NftBurn, error) {
+	var res []NftBurn
+	if err := decode(e, StandardNEP171, "nft_burn", &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DecodeNftTransfer decodes the data of a NEP-171 nft_transfer event.
+func DecodeNftTransfer(e Event) ([]NftTransfer, error) {
+	var res []NftTransfer
+	if err := decode(e, StandardNEP171, "nft_transfer", &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func decode(e Event, standard, event string, v interface{}) error {
+	if e.Standard != standard || e.Event != event {
+		return fmt.Errorf("expected %s %s event, got %s %s", standard, event, e.Standard, e.Event)
+	}
+	return e.DecodeData(v)
+}
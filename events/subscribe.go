@@ -0,0 +1,120 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/textileio/near-api-go"
+	"github.com/textileio/near-api-go/stream"
+	"github.com/textileio/near-api-go/transaction"
+)
+
+// BlockEvent is an Event found while scanning blocks.
+type BlockEvent struct {
+	Event
+	// BlockHeight and BlockHash identify the block where the receipt emitting the event executed.
+	// Heights of successive events may decrease, see Subscribe.
+	BlockHeight     int
+	BlockHash       string
+	TransactionHash string
+}
+
+// Subscribe follows final blocks and sends the events emitted by the provided contracts to the
+// events channel. For every transaction in a block that can execute contract code, the full
+// transaction outcome is fetched and the events of all receipt outcomes executed by one of the
+// contracts are sent, including those reached through cross-contract calls. Events are reported
+// with the block where their receipt executed, which may follow the block including the transaction.
+// Stream options such as stream.WithStartHeight and stream.WithCheckpointStore can be used to
+// resume a subscription. Subscribe closes the channel when it returns.
+//
+// The outcome is fetched once, when the block including the transaction is processed. Receipts
+// that haven't executed by then, like those of cross-contract calls in later blocks that aren't
+// final yet, are missing from it, so their events are never sent. Events are sent in the order of
+// the transactions, so their BlockHeight isn't monotonic: an event of a receipt executed in a
+// later block may be followed by events of the next transactions, reported with earlier heights.
+func Subscribe(
+	ctx context.Context,
+	client *api.Client,
+	contracts []string,
+	events chan<- BlockEvent,
+	opts ...stream.Option,
+) error {
+	defer close(events)
+	watched := make(map[string]struct{}, len(contracts))
+	for _, c := range contracts {
+		watched[c] = struct{}{}
+	}
+	s, err := stream.New(client, append(opts, stream.WithChunks(), stream.WithFinality("final"))...)
+	if err != nil {
+		return fmt.Errorf("creating stream: %v", err)
+	}
+	return s.Process(ctx, func(ctx context.Context, event stream.Event) error {
+		if event.Type != stream.EventBlock {
+			return nil
+		}
+		// Heights of the blocks where receipts executed, by hash.
+		heights := map[string]int{event.Hash: event.Height}
+		for _, chunk := range event.Chunks {
+			for _, tx := range chunk.Transactions {
+				if _, ok := watched[tx.ReceiverID]; !ok && !mayCallContracts(tx.Actions) {
+					continue
+				}
+				outcome, err := client.TransactionStatus(ctx, tx.Hash, tx.SignerID)
+				if err != nil {
+					return fmt.Errorf("getting status of transaction %s: %v", tx.Hash, err)
+				}
+				for _, o := range outcome.ReceiptsOutcome {
+					if _, ok := watched[o.Outcome.ExecutorID]; !ok {
+						continue
+					}
+					found := fromOutcomeWithID(o)
+					if len(found) == 0 {
+						continue
+					}
+					blockHash := o.BlockHash
+					if blockHash == "" {
+						blockHash = event.Hash
+					}
+					height, ok := heights[blockHash]
+					if !ok {
+						block, err := client.Block(ctx, api.BlockWithBlockHash(blockHash))
+						if err != nil {
+							return fmt.Errorf("getting block %s of receipt %s: %v", blockHash, o.ID, err)
+						}
+						height = block.Header.Height
+						heights[blockHash] = height
+					}
+					for _, e := range found {
+						be := BlockEvent{
+							Event:           e,
+							BlockHeight:     height,
+							BlockHash:       blockHash,
+							TransactionHash: tx.Hash,
+						}
+						select {
+						case events <- be:
+						case <-ctx.Done():
+							return ctx.Err()
+						}
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// mayCallContracts reports whether the actions can lead to contract code execution, and so to
+// events emitted by any contract through cross-contract calls.
+func mayCallContracts(actions []transaction.ActionView) bool {
+	for _, a := range actions {
+		switch a.Enum {
+		case 0, 1, 3, 4, 5, 6, 7:
+			// CreateAccount, DeployContract, Transfer, Stake, AddKey, DeleteKey and DeleteAccount
+			// don't execute code.
+		default:
+			return true
+		}
+	}
+	return false
+}
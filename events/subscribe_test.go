@@ -0,0 +1,129 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	api "github.com/textileio/near-api-go"
+	"github.com/textileio/near-api-go/jsonrpc"
+	"github.com/textileio/near-api-go/stream"
+	"github.com/textileio/near-api-go/types"
+)
+
+func TestSubscribe(t *testing.T) {
+	blocks := map[float64]interface{}{
+		1: map[string]interface{}{
+			"header": map[string]interface{}{"height": 1, "hash": "block-1"},
+			"chunks": []interface{}{map[string]interface{}{"chunk_hash": "chunk-1"}},
+		},
+		2: map[string]interface{}{
+			"header": map[string]interface{}{"height": 2, "hash": "block-2", "prev_hash": "block-1", "prev_height": 1},
+		},
+	}
+	outcome := func(id, blockHash, executor string, logs ...string) map[string]interface{} {
+		return map[string]interface{}{
+			"id":         id,
+			"block_hash": blockHash,
+			"outcome":    map[string]interface{}{"executor_id": executor, "logs": logs, "status": "Unknown"},
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		res := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "block":
+			var params struct {
+				Finality string      `json:"finality"`
+				BlockID  interface{} `json:"block_id"`
+			}
+			require.NoError(t, json.Unmarshal(req.Params, &params))
+			switch id := params.BlockID.(type) {
+			case float64:
+				res["result"] = blocks[id]
+			case string:
+				require.Equal(t, "block-2", id)
+				res["result"] = blocks[2]
+			default:
+				res["result"] = blocks[2]
+			}
+		case "chunk":
+			res["result"] = map[string]interface{}{
+				"header": map[string]interface{}{"chunk_hash": "chunk-1"},
+				"transactions": []interface{}{
+					map[string]interface{}{
+						"hash":        "tx-call",
+						"signer_id":   "alice.test",
+						"receiver_id": "router.test",
+						"actions": []interface{}{
+							map[string]interface{}{"FunctionCall": map[string]interface{}{
+								"method_name": "swap",
+								"args":        "",
+								"gas":         1,
+								"deposit":     "0",
+							}},
+						},
+					},
+					map[string]interface{}{
+						"hash":        "tx-transfer",
+						"signer_id":   "alice.test",
+						"receiver_id": "bob.test",
+						"actions":     []interface{}{map[string]interface{}{"Transfer": map[string]interface{}{"deposit": "1"}}},
+					},
+				},
+			}
+		case "tx":
+			var params []string
+			require.NoError(t, json.Unmarshal(req.Params, &params))
+			// Transactions that can't call contracts aren't fetched.
+			require.Equal(t, "tx-call", params[0])
+			res["result"] = map[string]interface{}{
+				"status":              map[string]interface{}{"SuccessValue": ""},
+				"transaction_outcome": outcome("tx-call", "block-1", "alice.test"),
+				"receipts_outcome": []interface{}{
+					outcome("r1", "block-1", "router.test", `EVENT_JSON:{"standard":"nep141","version":"1.0.0","event":"route"}`),
+					outcome("r2", "block-2", "token.test", `EVENT_JSON:{"standard":"nep141","version":"1.0.0","event":"ft_transfer"}`),
+				},
+			}
+		default:
+			res["error"] = map[string]interface{}{"code": -32601, "message": "method not found"}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(res))
+	}))
+	defer server.Close()
+	rpcClient, err := jsonrpc.DialHTTP(server.URL)
+	require.NoError(t, err)
+	defer rpcClient.Close()
+	client, err := api.NewClient(&types.Config{RPCClient: rpcClient, NetworkID: "testnet"})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	events := make(chan BlockEvent)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- Subscribe(ctx, client, []string{"token.test"}, events, stream.WithStartHeight(1))
+	}()
+	e, ok := <-events
+	if !ok {
+		require.NoError(t, <-errc)
+	}
+	cancel()
+	require.Equal(t, "token.test", e.Contract)
+	require.Equal(t, "ft_transfer", e.Event.Event)
+	require.Equal(t, 2, e.BlockHeight)
+	require.Equal(t, "block-2", e.BlockHash)
+	require.Equal(t, "tx-call", e.TransactionHash)
+	for range events {
+	}
+	require.ErrorIs(t, <-errc, context.Canceled)
+}
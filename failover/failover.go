@@ -0,0 +1,410 @@
+package failover
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	logging "github.com/textileio/go-log/v2"
+	"github.com/textileio/near-api-go/jsonrpc"
+	"github.com/textileio/near-api-go/util"
+)
+
+var (
+	log = logging.Logger("nearclient/failover")
+)
+
+// EndpointStatus holds information about the health of an endpoint.
+type EndpointStatus struct {
+	URL         string
+	Healthy     bool
+	BlockHeight int
+	Latency     time.Duration
+	Err         error
+	CheckedAt   time.Time
+}
+
+type endpoint struct {
+	url    string
+	status EndpointStatus
+}
+
+type pin struct {
+	endpoint *endpoint
+	expires  time.Time
+}
+
+// Provider routes JSON-RPC requests across several NEAR RPC endpoints.
+//
+// Requests are sent to healthy endpoints first, ordered by latency, and fail over
+// to the next endpoint on transport errors and HTTP 429 and 5xx responses.
+// Endpoints that fail are marked unhealthy until the next health check, which
+// calls status on every endpoint and compares their latest block heights.
+// Transactions sent with broadcast_tx_* are pinned to the endpoint they were
+// first sent to, so retrying a broadcast never submits it to a second node.
+//
+// Provider implements http.RoundTripper, use Client to create a jsonrpc.Client
+// for types.Config.
+type Provider struct {
+	cfg       config
+	endpoints []*endpoint
+
+	lk   sync.Mutex
+	pins map[string]pin
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New creates a Provider for the provided endpoint urls.
+// Close must be called to stop the periodic health checks.
+func New(urls []string, opts ...Option) (*Provider, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("you must provide at least one endpoint")
+	}
+	cfg := defaultConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	p := &Provider{
+		cfg:  cfg,
+		pins: make(map[string]pin),
+		done: make(chan struct{}),
+	}
+	for _, u := range urls {
+		for _, secret := range util.URLSecrets(u) {
+			util.AddSecret(secret)
+		}
+		p.endpoints = append(p.endpoints, &endpoint{
+			url:    u,
+			status: EndpointStatus{URL: u, Healthy: true},
+		})
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	if cfg.healthCheckInterval > 0 {
+		go p.healthCheckLoop(ctx)
+	} else {
+		close(p.done)
+	}
+	return p, nil
+}
+
+// Client creates a jsonrpc.Client that sends its requests through the Provider.
+func (p *Provider) Client() (*jsonrpc.HTTPClient, error) {
+	client, err := jsonrpc.DialHTTP(p.endpoints[0].url, jsonrpc.WithHTTPClient(&http.Client{
+		Transport: p,
+		Timeout:   p.cfg.httpClient.Timeout,
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("creating rpc client: %v", err)
+	}
+	return client, nil
+}
+
+// Close stops the periodic health checks, and unregisters the secrets of the endpoint urls.
+func (p *Provider) Close() error {
+	p.cancel()
+	<-p.done
+	for _, e := range p.endpoints {
+		for _, secret := range util.URLSecrets(e.url) {
+			util.RemoveSecret(secret)
+		}
+	}
+	return nil
+}
+
+// Status returns the latest known status of every endpoint.
+func (p *Provider) Status() []EndpointStatus {
+	p.lk.Lock()
+	defer p.lk.Unlock()
+	res := make([]EndpointStatus, len(p.endpoints))
+	for i, e := range p.endpoints {
+		res[i] = e.status
+	}
+	return res
+}
+
+// RoundTrip implements http.RoundTripper.
+func (p *Provider) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		b, err := ioutil.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading request body: %v", err)
+		}
+		body = b
+	}
+	if key, ok := broadcastKey(body); ok {
+		return p.roundTripBroadcast(req, body, key)
+	}
+
+	var lastErr error
+	candidates := p.candidates()
+	for i, e := range candidates {
+		resp, err := p.send(req, e, body)
+		if err != nil {
+			if req.Context().Err() != nil {
+				return nil, err
+			}
+			p.markDown(e, err)
+			lastErr = err
+			continue
+		}
+		if retryableStatus(resp.StatusCode) {
+			p.markDown(e, errors.New(resp.Status))
+			if i < len(candidates)-1 {
+				drain(resp)
+				continue
+			}
+		}
+		return resp, nil
+	}
+	return nil, fmt.Errorf("all endpoints failed: %v", lastErr)
+}
+
+func (p *Provider) roundTripBroadcast(req *http.Request, body []byte, key string) (*http.Response, error) {
+	p.lk.Lock()
+	pinned, ok := p.pins[key]
+	p.lk.Unlock()
+	if ok && time.Now().Before(pinned.expires) {
+		resp, err := p.send(req, pinned.endpoint, body)
+		if err == nil && retryableStatus(resp.StatusCode) {
+			p.markDown(pinned.endpoint, errors.New(resp.Status))
+		}
+		return resp, err
+	}
+
+	var lastErr error
+	candidates := p.candidates()
+	for i, e := range candidates {
+		resp, err := p.send(req, e, body)
+		if err != nil && isDialError(err) {
+			// The request never reached the endpoint, so it's safe to send it elsewhere.
+			p.markDown(e, err)
+			lastErr = err
+			continue
+		}
+		if err == nil && retryableStatus(resp.StatusCode) {
+			p.markDown(e, errors.New(resp.Status))
+			// A rate limited request wasn't processed, so it's also safe to send it elsewhere.
+			if resp.StatusCode == http.StatusTooManyRequests && i < len(candidates)-1 {
+				drain(resp)
+				continue
+			}
+		}
+		p.lk.Lock()
+		p.pins[key] = pin{endpoint: e, expires: time.Now().Add(p.cfg.pinTTL)}
+		p.lk.Unlock()
+		return resp, err
+	}
+	return nil, fmt.Errorf("all endpoints failed: %v", lastErr)
+}
+
+func (p *Provider) send(req *http.Request, e *endpoint, body []byte) (*http.Response, error) {
+	r, err := http.NewRequestWithContext(req.Context(), req.Method, e.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %v", err)
+	}
+	for k, v := range req.Header {
+		r.Header[k] = v
+	}
+	return p.cfg.httpClient.Do(r)
+}
+
+// candidates returns the endpoints in the order they should be tried.
+func (p *Provider) candidates() []*endpoint {
+	p.lk.Lock()
+	defer p.lk.Unlock()
+	res := make([]*endpoint, len(p.endpoints))
+	copy(res, p.endpoints)
+	sort.SliceStable(res, func(i, j int) bool {
+		a, b := res[i].status, res[j].status
+		if a.Healthy != b.Healthy {
+			return a.Healthy
+		}
+		return a.Latency < b.Latency
+	})
+	return res
+}
+
+func (p *Provider) markDown(e *endpoint, err error) {
+	err = util.RedactError(err)
+	log.Warnf("endpoint %s failed: %v", util.Redact(e.url), err)
+	p.lk.Lock()
+	defer p.lk.Unlock()
+	e.status.Healthy = false
+	e.status.Err = err
+}
+
+func (p *Provider) healthCheckLoop(ctx context.Context) {
+	defer close(p.done)
+	ticker := time.NewTicker(p.cfg.healthCheckInterval)
+	defer ticker.Stop()
+	for {
+		p.CheckHealth(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckHealth checks the health of every endpoint and returns their updated status.
+// An endpoint is healthy if its status call succeeds, it isn't syncing and its latest
+// block height is within the configured lag of the most up to date endpoint.
+func (p *Provider) CheckHealth(ctx context.Context) []EndpointStatus {
+	statuses := make([]EndpointStatus, len(p.endpoints))
+	var wg sync.WaitGroup
+	for i, e := range p.endpoints {
+		wg.Add(1)
+		go func(i int, e *endpoint) {
+			defer wg.Done()
+			statuses[i] = p.checkEndpoint(ctx, e)
+		}(i, e)
+	}
+	wg.Wait()
+
+	maxHeight := 0
+	for _, s := range statuses {
+		if s.Err == nil && s.BlockHeight > maxHeight {
+			maxHeight = s.BlockHeight
+		}
+	}
+	for i := range statuses {
+		s := &statuses[i]
+		if s.Err == nil && maxHeight-s.BlockHeight > p.cfg.maxBlockLag {
+			s.Err = fmt.Errorf("lagging %d blocks behind", maxHeight-s.BlockHeight)
+		}
+		s.Healthy = s.Err == nil
+	}
+
+	p.lk.Lock()
+	defer p.lk.Unlock()
+	for i, e := range p.endpoints {
+		e.status = statuses[i]
+	}
+	now := time.Now()
+	for key, pinned := range p.pins {
+		if now.After(pinned.expires) {
+			delete(p.pins, key)
+		}
+	}
+	return statuses
+}
+
+func (p *Provider) checkEndpoint(ctx context.Context, e *endpoint) EndpointStatus {
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.healthCheckTimeout)
+	defer cancel()
+	status := EndpointStatus{URL: e.url, CheckedAt: time.Now()}
+	height, err := p.latestBlockHeight(ctx, e.url)
+	status.Latency = time.Since(status.CheckedAt)
+	if err != nil {
+		status.Err = util.RedactError(err)
+		return status
+	}
+	status.BlockHeight = height
+	return status
+}
+
+func (p *Provider) latestBlockHeight(ctx context.Context, url string) (int, error) {
+	body := strings.NewReader(`{"jsonrpc":"2.0","id":"health","method":"status","params":[]}`)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return 0, fmt.Errorf("creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.cfg.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("calling status: %v", err)
+	}
+	defer drain(resp)
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("calling status: %s", resp.Status)
+	}
+	var res struct {
+		Result *struct {
+			SyncInfo struct {
+				LatestBlockHeight int  `json:"latest_block_height"`
+				Syncing           bool `json:"syncing"`
+			} `json:"sync_info"`
+		} `json:"result"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return 0, fmt.Errorf("decoding status: %v", err)
+	}
+	if res.Error != nil {
+		return 0, fmt.Errorf("calling status: %s", res.Error.Message)
+	}
+	if res.Result == nil {
+		return 0, fmt.Errorf("calling status: empty result")
+	}
+	if res.Result.SyncInfo.Syncing {
+		return 0, fmt.Errorf("node is syncing")
+	}
+	return res.Result.SyncInfo.LatestBlockHeight, nil
+}
+
+type jsonrpcMessage struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// broadcastKey returns a key identifying the transactions broadcast by the request body.
+func broadcastKey(body []byte) (string, bool) {
+	var msgs []jsonrpcMessage
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &msgs); err != nil {
+			return "", false
+		}
+	} else {
+		var msg jsonrpcMessage
+		if err := json.Unmarshal(trimmed, &msg); err != nil {
+			return "", false
+		}
+		msgs = append(msgs, msg)
+	}
+	h := sha256.New()
+	found := false
+	for _, msg := range msgs {
+		if strings.HasPrefix(msg.Method, "broadcast_tx_") {
+			found = true
+			_, _ = h.Write(msg.Params)
+		}
+	}
+	if !found {
+		return "", false
+	}
+	return string(h.Sum(nil)), true
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func drain(resp *http.Response) {
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,137 @@
+package failover
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type node struct {
+	height   int
+	syncing  bool
+	failing  int32
+	requests int32
+	server   *httptest.Server
+}
+
+func newNode(t *testing.T, height int) *node {
+	n := &node{height: height}
+	n.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.Method != "status" {
+			atomic.AddInt32(&n.requests, 1)
+		}
+		if atomic.LoadInt32(&n.failing) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var result interface{} = "ok"
+		if req.Method == "status" {
+			result = map[string]interface{}{
+				"sync_info": map[string]interface{}{"latest_block_height": n.height, "syncing": n.syncing},
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(n.server.Close)
+	return n
+}
+
+func makeProvider(t *testing.T, nodes ...*node) *Provider {
+	var urls []string
+	for _, n := range nodes {
+		urls = append(urls, n.server.URL)
+	}
+	p, err := New(urls, WithHealthCheckInterval(0), WithMaxBlockLag(5))
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, p.Close()) })
+	return p
+}
+
+func TestFailover(t *testing.T) {
+	a, b := newNode(t, 100), newNode(t, 100)
+	p := makeProvider(t, a, b)
+	client, err := p.Client()
+	require.NoError(t, err)
+
+	var res string
+	require.NoError(t, client.CallContext(context.Background(), &res, "block"))
+	require.Equal(t, "ok", res)
+	require.Equal(t, int32(1), atomic.LoadInt32(&a.requests))
+
+	atomic.StoreInt32(&a.failing, 1)
+	require.NoError(t, client.CallContext(context.Background(), &res, "block"))
+	require.Equal(t, int32(1), atomic.LoadInt32(&b.requests))
+	require.False(t, p.Status()[0].Healthy)
+
+	// The failed endpoint is skipped until it passes a health check.
+	require.NoError(t, client.CallContext(context.Background(), &res, "block"))
+	require.Equal(t, int32(2), atomic.LoadInt32(&a.requests))
+	require.Equal(t, int32(2), atomic.LoadInt32(&b.requests))
+
+	atomic.StoreInt32(&a.failing, 0)
+	p.CheckHealth(context.Background())
+	require.True(t, p.Status()[0].Healthy)
+}
+
+func TestCheckHealth(t *testing.T) {
+	a, b, c := newNode(t, 100), newNode(t, 110), newNode(t, 110)
+	c.syncing = true
+	p := makeProvider(t, a, b, c)
+
+	statuses := p.CheckHealth(context.Background())
+	require.Len(t, statuses, 3)
+	require.False(t, statuses[0].Healthy)
+	require.Error(t, statuses[0].Err)
+	require.True(t, statuses[1].Healthy)
+	require.Equal(t, 110, statuses[1].BlockHeight)
+	require.False(t, statuses[2].Healthy)
+
+	client, err := p.Client()
+	require.NoError(t, err)
+	var res string
+	require.NoError(t, client.CallContext(context.Background(), &res, "block"))
+	require.Equal(t, int32(0), atomic.LoadInt32(&a.requests))
+	require.Equal(t, int32(1), atomic.LoadInt32(&b.requests))
+}
+
+func TestBroadcastPinned(t *testing.T) {
+	a, b := newNode(t, 100), newNode(t, 100)
+	p := makeProvider(t, a, b)
+	client, err := p.Client()
+	require.NoError(t, err)
+
+	var res string
+	require.NoError(t, client.CallContext(context.Background(), &res, "broadcast_tx_commit", "dHg="))
+	require.Equal(t, int32(1), atomic.LoadInt32(&a.requests))
+
+	// Retrying the same transaction must not be sent to another endpoint.
+	atomic.StoreInt32(&a.failing, 1)
+	err = client.CallContext(context.Background(), &res, "broadcast_tx_commit", "dHg=")
+	require.Error(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&a.requests))
+	require.Equal(t, int32(0), atomic.LoadInt32(&b.requests))
+
+	// Other transactions aren't pinned and avoid the failed endpoint.
+	require.NoError(t, client.CallContext(context.Background(), &res, "broadcast_tx_commit", "dHgy"))
+	require.Equal(t, int32(1), atomic.LoadInt32(&b.requests))
+}
+
+func TestBroadcastKey(t *testing.T) {
+	k1, ok := broadcastKey([]byte(`{"method":"broadcast_tx_async","params":["a"]}`))
+	require.True(t, ok)
+	k2, ok := broadcastKey([]byte(`[{"method":"broadcast_tx_async","params":["a"]}]`))
+	require.True(t, ok)
+	require.Equal(t, k1, k2)
+	_, ok = broadcastKey([]byte(`{"method":"query","params":{}}`))
+	require.False(t, ok)
+}
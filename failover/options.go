@@ -0,0 +1,62 @@
+package failover
+
+import (
+	"net/http"
+	"time"
+)
+
+type config struct {
+	httpClient          *http.Client
+	healthCheckInterval time.Duration
+	healthCheckTimeout  time.Duration
+	maxBlockLag         int
+	pinTTL              time.Duration
+}
+
+var defaultConfig = config{
+	httpClient:          http.DefaultClient,
+	healthCheckInterval: 15 * time.Second,
+	healthCheckTimeout:  5 * time.Second,
+	maxBlockLag:         10,
+	pinTTL:              10 * time.Minute,
+}
+
+// Option controls the behavior of a Provider.
+type Option func(*config)
+
+// WithHTTPClient specifies the http.Client used to reach the endpoints.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *config) {
+		c.httpClient = client
+	}
+}
+
+// WithHealthCheckInterval specifies how often the endpoints are checked.
+// Periodic health checks are disabled if the interval is zero.
+func WithHealthCheckInterval(interval time.Duration) Option {
+	return func(c *config) {
+		c.healthCheckInterval = interval
+	}
+}
+
+// WithHealthCheckTimeout specifies the timeout of a single endpoint health check.
+func WithHealthCheckTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.healthCheckTimeout = timeout
+	}
+}
+
+// WithMaxBlockLag specifies how many blocks an endpoint can lag behind
+// the most up to date endpoint before it is considered unhealthy.
+func WithMaxBlockLag(blocks int) Option {
+	return func(c *config) {
+		c.maxBlockLag = blocks
+	}
+}
+
+// WithPinTTL specifies how long a broadcast transaction stays pinned to the endpoint it was first sent to.
+func WithPinTTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.pinTTL = ttl
+	}
+}
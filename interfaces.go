@@ -0,0 +1,20 @@
+package api
+
+import (
+	"context"
+)
+
+// Viewer queries the chain without sending transactions.
+type Viewer interface {
+	CallFunction(
+		ctx context.Context,
+		accountID string,
+		methodName string,
+		opts ...CallFunctionOption,
+	) (*CallFunctionResponse, error)
+	ViewCode(ctx context.Context, accountID string, opts ...ViewCodeOption) (*ViewCodeResponse, error)
+	DataChanges(ctx context.Context, accountIDs []string, opts ...DataChangesOption) (*DataChangesResponse, error)
+	NodeStatus(ctx context.Context) (*NodeStatusResponse, error)
+}
+
+var _ Viewer = (*Client)(nil)
@@ -0,0 +1,170 @@
+package call
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/textileio/near-api-go/cache"
+	"github.com/textileio/near-api-go/jsonrpc"
+	"github.com/textileio/near-api-go/types"
+	"github.com/textileio/near-api-go/util"
+)
+
+// RPC calls a RPC method, waiting on the config's rate limiter before every attempt
+// and retrying transient errors according to the config's retry policy.
+func RPC(ctx context.Context, config *types.Config, result interface{}, method string, args ...interface{}) error {
+	return withRetries(ctx, config, func(ctx context.Context) error {
+		return config.RPCClient.CallContext(ctx, result, method, args...)
+	})
+}
+
+// Block calls a RPC method that references a block, like RPC. If the node reports the block
+// as unknown or garbage collected and the config has an archival RPC client,
+// the call is retried against the archival node.
+//
+// If the config has a cache, responses for blocks referenced by hash, or by a height
+// that's known to be final, are cached.
+func Block(ctx context.Context, config *types.Config, result interface{}, method string, args ...interface{}) error {
+	if config.Cache == nil {
+		return block(ctx, config, result, method, args...)
+	}
+	ref, key, ok := cacheKey(method, args)
+	cacheable := ok && isImmutable(config.Cache, ref)
+	if cacheable {
+		if value, hit := config.Cache.Get(key); hit {
+			return json.Unmarshal(value, result)
+		}
+	}
+	var raw json.RawMessage
+	if err := block(ctx, config, &raw, method, args...); err != nil {
+		return err
+	}
+	if ok && method == "block" && ref.Finality() == types.FinalityFinal {
+		var res struct {
+			Header struct {
+				Height int `json:"height"`
+			} `json:"header"`
+		}
+		if err := json.Unmarshal(raw, &res); err == nil {
+			config.Cache.ObserveFinalHeight(res.Header.Height)
+		}
+	}
+	if cacheable && !hasBodyError(raw) {
+		config.Cache.Add(key, raw)
+	}
+	return json.Unmarshal(raw, result)
+}
+
+func block(ctx context.Context, config *types.Config, result interface{}, method string, args ...interface{}) error {
+	err := RPC(ctx, config, result, method, args...)
+	if err == nil || config.ArchivalRPCClient == nil || ctx.Err() != nil {
+		return err
+	}
+	if !isArchivalError(err) {
+		return err
+	}
+	return withRetries(ctx, config, func(ctx context.Context) error {
+		return config.ArchivalRPCClient.CallContext(ctx, result, method, args...)
+	})
+}
+
+// cacheKey returns the block referenced by the named params of a request, and the key caching its response.
+func cacheKey(method string, args []interface{}) (types.BlockReference, string, bool) {
+	if len(args) != 1 {
+		return types.BlockReference{}, "", false
+	}
+	params, ok := args[0].(jsonrpc.NamedParams)
+	if !ok {
+		return types.BlockReference{}, "", false
+	}
+	b, err := json.Marshal(params.Value)
+	if err != nil {
+		return types.BlockReference{}, "", false
+	}
+	var req struct {
+		Finality string      `json:"finality"`
+		BlockID  interface{} `json:"block_id"`
+	}
+	if err := json.Unmarshal(b, &req); err != nil {
+		return types.BlockReference{}, "", false
+	}
+	if height, ok := req.BlockID.(float64); ok {
+		req.BlockID = int(height)
+	}
+	ref, err := types.NewBlockReference(req.Finality, req.BlockID)
+	if err != nil {
+		return types.BlockReference{}, "", false
+	}
+	return ref, method + " " + string(b), true
+}
+
+func isImmutable(c *cache.Cache, ref types.BlockReference) bool {
+	if _, ok := ref.Hash(); ok {
+		return true
+	}
+	height, ok := ref.Height()
+	return ok && height <= c.FinalHeight()
+}
+
+func hasBodyError(raw json.RawMessage) bool {
+	var res struct {
+		Error string `json:"error"`
+	}
+	return json.Unmarshal(raw, &res) == nil && res.Error != ""
+}
+
+func withRetries(ctx context.Context, config *types.Config, run func(ctx context.Context) error) error {
+	attempt := func(ctx context.Context) error {
+		if config.RateLimiter != nil {
+			if err := config.RateLimiter.Wait(ctx); err != nil {
+				return err
+			}
+		}
+		return run(ctx)
+	}
+	if config.RetryPolicy == nil {
+		return attempt(ctx)
+	}
+	return config.RetryPolicy.Do(ctx, attempt)
+}
+
+// Batch sends the elements as a single batch request, like RPC. Elements failing because
+// the node reports their block as unknown or garbage collected are sent again to the
+// archival node if the config has an archival RPC client.
+func Batch(ctx context.Context, config *types.Config, elems []jsonrpc.BatchElem) error {
+	if err := withRetries(ctx, config, func(ctx context.Context) error {
+		return config.RPCClient.BatchCallContext(ctx, elems)
+	}); err != nil {
+		return err
+	}
+	if config.ArchivalRPCClient == nil {
+		return nil
+	}
+	var archival []jsonrpc.BatchElem
+	var indexes []int
+	for i, elem := range elems {
+		if isArchivalError(elem.Error) {
+			elem.Error = nil
+			archival = append(archival, elem)
+			indexes = append(indexes, i)
+		}
+	}
+	if len(archival) == 0 {
+		return nil
+	}
+	if err := withRetries(ctx, config, func(ctx context.Context) error {
+		return config.ArchivalRPCClient.BatchCallContext(ctx, archival)
+	}); err != nil {
+		return err
+	}
+	for i, elem := range archival {
+		elems[indexes[i]] = elem
+	}
+	return nil
+}
+
+// isArchivalError reports whether the error means the block referenced by the request may
+// be available from an archival node.
+func isArchivalError(err error) bool {
+	return util.IsUnknownBlockError(err) || util.IsGarbageCollectedError(err)
+}
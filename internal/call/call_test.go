@@ -0,0 +1,157 @@
+package call
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/textileio/near-api-go/cache"
+	"github.com/textileio/near-api-go/jsonrpc"
+	"github.com/textileio/near-api-go/types"
+	"github.com/textileio/near-api-go/util"
+)
+
+func makeRPCClient(t *testing.T, result interface{}, rpcErr map[string]interface{}) (*jsonrpc.HTTPClient, *int) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		res := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		if rpcErr != nil {
+			res["error"] = rpcErr
+		} else {
+			res["result"] = result
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(res))
+	}))
+	t.Cleanup(server.Close)
+	client, err := jsonrpc.DialHTTP(server.URL)
+	require.NoError(t, err)
+	return client, &calls
+}
+
+func TestBlockArchivalFallback(t *testing.T) {
+	gcErr := map[string]interface{}{
+		"code":    -32000,
+		"message": "Server error",
+		"data":    "DB Not Found Error: BLOCK HEIGHT: 5",
+	}
+	regular, regularCalls := makeRPCClient(t, nil, gcErr)
+	archival, archivalCalls := makeRPCClient(t, "archived", nil)
+
+	var res string
+	err := Block(context.Background(), &types.Config{RPCClient: regular}, &res, "block")
+	require.Error(t, err)
+	require.Equal(t, 1, *regularCalls)
+
+	config := &types.Config{RPCClient: regular, ArchivalRPCClient: archival}
+	require.NoError(t, Block(context.Background(), config, &res, "block"))
+	require.Equal(t, "archived", res)
+	require.Equal(t, 2, *regularCalls)
+	require.Equal(t, 1, *archivalCalls)
+}
+
+func TestBlockArchivalFallbackGarbageCollected(t *testing.T) {
+	regular, _ := makeRPCClient(t, nil, map[string]interface{}{
+		"code":    -32000,
+		"message": "Server error",
+		"data":    "The data for block #5 is garbage collected on this node, use an archival node to fetch historical data",
+		"cause":   map[string]interface{}{"name": "GARBAGE_COLLECTED_BLOCK"},
+	})
+	archival, archivalCalls := makeRPCClient(t, "archived", nil)
+
+	var res string
+	config := &types.Config{RPCClient: regular, ArchivalRPCClient: archival}
+	require.NoError(t, Block(context.Background(), config, &res, "block"))
+	require.Equal(t, "archived", res)
+	require.Equal(t, 1, *archivalCalls)
+}
+
+func TestBlockNoFallbackOnOtherErrors(t *testing.T) {
+	regular, _ := makeRPCClient(t, nil, map[string]interface{}{
+		"code":    -32000,
+		"message": "Server error",
+		"data":    "account foo.testnet does not exist while viewing",
+	})
+	archival, archivalCalls := makeRPCClient(t, "archived", nil)
+
+	var res string
+	config := &types.Config{RPCClient: regular, ArchivalRPCClient: archival}
+	require.Error(t, Block(context.Background(), config, &res, "query"))
+	require.Equal(t, 0, *archivalCalls)
+}
+
+func TestRPCRetries(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"ok"}`))
+	}))
+	t.Cleanup(server.Close)
+	client, err := jsonrpc.DialHTTP(server.URL)
+	require.NoError(t, err)
+
+	var res string
+	config := &types.Config{RPCClient: client}
+	require.Error(t, RPC(context.Background(), config, &res, "status"))
+	require.Equal(t, 1, calls)
+
+	config.RetryPolicy = &util.RetryPolicy{MaxAttempts: 5, InitialInterval: time.Millisecond, Multiplier: 1}
+	config.RateLimiter = util.NewRateLimiter(1000, 1)
+	require.NoError(t, RPC(context.Background(), config, &res, "status"))
+	require.Equal(t, "ok", res)
+	require.Equal(t, 3, calls)
+}
+
+func TestBlockCache(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  map[string]interface{}{"header": map[string]interface{}{"height": 100}},
+		}))
+	}))
+	t.Cleanup(server.Close)
+	client, err := jsonrpc.DialHTTP(server.URL)
+	require.NoError(t, err)
+	config := &types.Config{RPCClient: client, Cache: cache.New()}
+
+	params := func(ref types.BlockReference) jsonrpc.NamedParams {
+		return jsonrpc.NewNamedParams(map[string]interface{}{"finality": string(ref.Finality()), "block_id": ref.BlockID()})
+	}
+	var res map[string]interface{}
+	for i := 0; i < 2; i++ {
+		require.NoError(t, Block(context.Background(), config, &res, "block", params(types.BlockHash("abc"))))
+		require.NotNil(t, res["header"])
+	}
+	require.Equal(t, 1, calls)
+
+	// Heights aren't cached until they are known to be final.
+	require.NoError(t, Block(context.Background(), config, &res, "block", params(types.BlockHeight(90))))
+	require.Equal(t, 2, calls)
+	require.NoError(t, Block(context.Background(), config, &res, "block", params(types.Final())))
+	require.Equal(t, 3, calls)
+	require.Equal(t, 100, config.Cache.FinalHeight())
+	for i := 0; i < 2; i++ {
+		require.NoError(t, Block(context.Background(), config, &res, "block", params(types.BlockHeight(90))))
+	}
+	require.Equal(t, 4, calls)
+	require.Equal(t, cache.Stats{Hits: 2, Misses: 2}, config.Cache.Stats())
+}
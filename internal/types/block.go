@@ -0,0 +1,14 @@
+package types
+
+import (
+	"github.com/textileio/near-api-go/types"
+)
+
+// CheckBlockReference validates the finality and block id fields of a request.
+func CheckBlockReference(finality string, blockID interface{}) error {
+	ref, err := types.NewBlockReference(finality, blockID)
+	if err != nil {
+		return err
+	}
+	return ref.Validate()
+}
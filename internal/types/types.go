@@ -35,6 +35,35 @@ type BlockRequest struct {
 	Finality string      `json:"finality,omitempty"`
 }
 
+// ChunkRequest is used for RPC chunk requests.
+type ChunkRequest struct {
+	ChunkID string      `json:"chunk_id,omitempty"`
+	BlockID interface{} `json:"block_id,omitempty"`
+	ShardID *int        `json:"shard_id,omitempty"`
+	// Finality isn't supported by the RPC, it's only kept to reject finality block references.
+	Finality string `json:"-"`
+}
+
+// ReceiptRequest is used for RPC receipt requests.
+type ReceiptRequest struct {
+	ReceiptID string `json:"receipt_id"`
+}
+
+// ValidatorsRequest is used for RPC validators requests.
+type ValidatorsRequest struct {
+	BlockID interface{} `json:"block_id,omitempty"`
+	EpochID string      `json:"epoch_id,omitempty"`
+	// Finality isn't supported by the RPC, it's only kept to reject finality block references.
+	Finality string `json:"-"`
+}
+
+// GasPriceRequest is used for RPC gas price requests.
+type GasPriceRequest struct {
+	BlockID interface{} `json:"block_id"`
+	// Finality isn't supported by the RPC, it's only kept to reject finality block references.
+	Finality string `json:"-"`
+}
+
 // BlockHeader contains information about a block header.
 type BlockHeader struct {
 	Height                int           `json:"height"`
@@ -0,0 +1,74 @@
+// Package gethrpc adapts a go-ethereum rpc.Client to jsonrpc.Client, for code that
+// already creates its clients with go-ethereum.
+package gethrpc
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/textileio/near-api-go/jsonrpc"
+)
+
+// Client is a jsonrpc.Client backed by a go-ethereum rpc.Client.
+type Client struct {
+	client *rpc.Client
+}
+
+var _ jsonrpc.Client = (*Client)(nil)
+
+// Wrap creates a Client backed by the provided go-ethereum rpc.Client.
+func Wrap(client *rpc.Client) *Client {
+	return &Client{client: client}
+}
+
+// Dial creates a Client connected to the provided url with go-ethereum.
+func Dial(ctx context.Context, url string) (*Client, error) {
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return Wrap(client), nil
+}
+
+// Unwrap returns the go-ethereum rpc.Client.
+func (c *Client) Unwrap() *rpc.Client {
+	return c.client
+}
+
+// CallContext implements jsonrpc.Client.
+func (c *Client) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return c.client.CallContext(ctx, result, method, convertArgs(args)...)
+}
+
+// BatchCallContext implements jsonrpc.Client.
+func (c *Client) BatchCallContext(ctx context.Context, b []jsonrpc.BatchElem) error {
+	elems := make([]rpc.BatchElem, len(b))
+	for i, elem := range b {
+		elems[i] = rpc.BatchElem{
+			Method: elem.Method,
+			Args:   convertArgs(elem.Args),
+			Result: elem.Result,
+		}
+	}
+	if err := c.client.BatchCallContext(ctx, elems); err != nil {
+		return err
+	}
+	for i, elem := range elems {
+		b[i].Error = elem.Error
+	}
+	return nil
+}
+
+// Close implements jsonrpc.Client.
+func (c *Client) Close() {
+	c.client.Close()
+}
+
+func convertArgs(args []interface{}) []interface{} {
+	if len(args) == 1 {
+		if named, ok := args[0].(jsonrpc.NamedParams); ok {
+			return []interface{}{rpc.NewNamedParams(named.Value)}
+		}
+	}
+	return args
+}
@@ -0,0 +1,56 @@
+package gethrpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/textileio/near-api-go/jsonrpc"
+)
+
+func TestClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []struct {
+			ID     json.RawMessage `json:"id"`
+			Params json.RawMessage `json:"params"`
+		}
+		var raw json.RawMessage
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&raw))
+		batch := raw[0] == '['
+		if !batch {
+			raw = append(append([]byte("["), raw...), ']')
+		}
+		require.NoError(t, json.Unmarshal(raw, &reqs))
+		var res []interface{}
+		for _, req := range reqs {
+			res = append(res, map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": req.Params})
+		}
+		if batch {
+			require.NoError(t, json.NewEncoder(w).Encode(res))
+		} else {
+			require.NoError(t, json.NewEncoder(w).Encode(res[0]))
+		}
+	}))
+	defer server.Close()
+	c, err := Dial(context.Background(), server.URL)
+	require.NoError(t, err)
+	defer c.Close()
+
+	var params json.RawMessage
+	err = c.CallContext(context.Background(), &params, "block", jsonrpc.NewNamedParams(map[string]int{"block_id": 1}))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"block_id": 1}`, string(params))
+
+	var p1, p2 json.RawMessage
+	elems := []jsonrpc.BatchElem{
+		{Method: "block", Args: []interface{}{jsonrpc.NewNamedParams(map[string]int{"block_id": 2})}, Result: &p1},
+		{Method: "broadcast_tx_commit", Args: []interface{}{"tx"}, Result: &p2},
+	}
+	require.NoError(t, c.BatchCallContext(context.Background(), elems))
+	require.NoError(t, elems[0].Error)
+	require.JSONEq(t, `{"block_id": 2}`, string(p1))
+	require.JSONEq(t, `["tx"]`, string(p2))
+}
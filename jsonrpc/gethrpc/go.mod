@@ -0,0 +1,11 @@
+module github.com/textileio/near-api-go/jsonrpc/gethrpc
+
+go 1.16
+
+require (
+	github.com/ethereum/go-ethereum v1.10.6
+	github.com/stretchr/testify v1.7.0
+	github.com/textileio/near-api-go v0.2.0
+)
+
+replace github.com/ethereum/go-ethereum => github.com/textileio/go-ethereum v1.10.3-0.20210413172519-62e8b38d82b1
@@ -0,0 +1,290 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/textileio/near-api-go/util"
+)
+
+const maxErrorBodySize = 1 << 16
+
+// TokenProvider returns the bearer token sent with a request. It's called before every
+// request, so it can return rotated tokens, and should cache them as needed.
+type TokenProvider func(ctx context.Context) (string, error)
+
+type config struct {
+	httpClient    *http.Client
+	header        http.Header
+	secrets       []string
+	tokenProvider TokenProvider
+	timeout       time.Duration
+}
+
+// Option controls the behavior of a HTTPClient.
+type Option func(*config)
+
+// WithHTTPClient specifies the http.Client used to send requests. Its connections are left
+// open by Close. Defaults to a client with its own transport, closed by Close.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *config) {
+		c.httpClient = client
+	}
+}
+
+// WithHeader adds a header sent with every request, like an API key.
+func WithHeader(key, value string) Option {
+	return func(c *config) {
+		c.header.Add(key, value)
+	}
+}
+
+// WithSecretHeader adds a header sent with every request, like WithHeader, and redacts its
+// value from errors and logs.
+func WithSecretHeader(key, value string) Option {
+	return func(c *config) {
+		c.header.Add(key, value)
+		c.secrets = append(c.secrets, value)
+	}
+}
+
+// WithSecret redacts a secret from errors and logs, like an API key that's part of the url path.
+// The password of the url, and the values of its credential query parameters (apikey, api_key, key,
+// token and access_token), are redacted without this option. Other query parameters aren't.
+func WithSecret(secret string) Option {
+	return func(c *config) {
+		c.secrets = append(c.secrets, secret)
+	}
+}
+
+// WithTokenProvider sends the token returned by the provider as a bearer token in
+// the Authorization header of every request. Tokens are redacted from errors and logs.
+func WithTokenProvider(provider TokenProvider) Option {
+	return func(c *config) {
+		c.tokenProvider = provider
+	}
+}
+
+// WithTimeout specifies the timeout of every request. Requests only time out with
+// their context if zero, the default.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.timeout = timeout
+	}
+}
+
+// HTTPClient is a Client sending JSON-RPC requests over HTTP.
+//
+// Secrets, like the values of WithSecretHeader and the tokens of WithTokenProvider,
+// are registered with util.AddSecret until the client is closed, so they're redacted
+// from the errors returned by the client and by util.MapRPCError.
+type HTTPClient struct {
+	url string
+	cfg config
+	id  uint64
+	// ownsHTTPClient is true if the http.Client wasn't provided with WithHTTPClient.
+	ownsHTTPClient bool
+
+	lk sync.Mutex
+	// tokens are the latest tokens returned by the token provider, newest last.
+	tokens []string
+	closed bool
+}
+
+var _ Client = (*HTTPClient)(nil)
+
+// DialHTTP creates a HTTPClient for the node at the provided url.
+func DialHTTP(rawURL string, opts ...Option) (*HTTPClient, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing url: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported url scheme %q", u.Scheme)
+	}
+	cfg := config{header: make(http.Header)}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	ownsHTTPClient := cfg.httpClient == nil
+	if ownsHTTPClient {
+		cfg.httpClient = &http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()}
+	}
+	cfg.secrets = append(cfg.secrets, util.URLSecrets(rawURL)...)
+	for _, secret := range cfg.secrets {
+		util.AddSecret(secret)
+	}
+	return &HTTPClient{url: rawURL, cfg: cfg, ownsHTTPClient: ownsHTTPClient}, nil
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      uint64          `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type response struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *Error          `json:"error"`
+}
+
+// CallContext implements Client.
+func (c *HTTPClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	req, err := c.newRequest(method, args)
+	if err != nil {
+		return err
+	}
+	var res response
+	if err := c.send(ctx, req, &res); err != nil {
+		return err
+	}
+	return res.decode(result)
+}
+
+// BatchCallContext implements Client.
+func (c *HTTPClient) BatchCallContext(ctx context.Context, b []BatchElem) error {
+	reqs := make([]*request, len(b))
+	byID := make(map[string]int, len(b))
+	for i, elem := range b {
+		req, err := c.newRequest(elem.Method, elem.Args)
+		if err != nil {
+			return err
+		}
+		reqs[i] = req
+		byID[fmt.Sprint(req.ID)] = i
+	}
+	var resps []response
+	if err := c.send(ctx, reqs, &resps); err != nil {
+		return err
+	}
+	for i := range b {
+		b[i].Error = errors.New("missing response")
+	}
+	for _, res := range resps {
+		i, ok := byID[string(res.ID)]
+		if !ok {
+			continue
+		}
+		b[i].Error = res.decode(b[i].Result)
+	}
+	return nil
+}
+
+// Close implements Client. It unregisters the secrets of the client and closes the idle
+// connections of the http.Client, unless it was provided with WithHTTPClient.
+func (c *HTTPClient) Close() {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	if c.ownsHTTPClient {
+		c.cfg.httpClient.CloseIdleConnections()
+	}
+	for _, secret := range c.cfg.secrets {
+		util.RemoveSecret(secret)
+	}
+	for _, token := range c.tokens {
+		util.RemoveSecret(token)
+	}
+	c.tokens = nil
+}
+
+// token returns the token of the token provider, and registers it as a secret.
+// The two latest tokens are kept registered, so errors of requests sent with
+// the previous token are still redacted after a rotation.
+func (c *HTTPClient) token(ctx context.Context) (string, error) {
+	token, err := c.cfg.tokenProvider(ctx)
+	if err != nil {
+		return "", util.RedactError(fmt.Errorf("getting token: %v", err))
+	}
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	if c.closed || (len(c.tokens) > 0 && c.tokens[len(c.tokens)-1] == token) {
+		return token, nil
+	}
+	util.AddSecret(token)
+	c.tokens = append(c.tokens, token)
+	if len(c.tokens) > 2 {
+		util.RemoveSecret(c.tokens[0])
+		c.tokens = c.tokens[1:]
+	}
+	return token, nil
+}
+
+func (c *HTTPClient) newRequest(method string, args []interface{}) (*request, error) {
+	p, err := params(args)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling params: %v", err)
+	}
+	return &request{
+		JSONRPC: "2.0",
+		ID:      atomic.AddUint64(&c.id, 1),
+		Method:  method,
+		Params:  p,
+	}, nil
+}
+
+func (c *HTTPClient) send(ctx context.Context, body interface{}, result interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshaling request: %v", err)
+	}
+	if c.cfg.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.cfg.timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("creating request: %v", err)
+	}
+	for k, v := range c.cfg.header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.cfg.tokenProvider != nil {
+		token, err := c.token(ctx)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := c.cfg.httpClient.Do(req)
+	if err != nil {
+		return util.RedactError(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return &HTTPError{StatusCode: resp.StatusCode, Status: resp.Status, Body: []byte(util.Redact(string(respBody)))}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("decoding response: %v", err)
+	}
+	return nil
+}
+
+func (r *response) decode(result interface{}) error {
+	if r.Error != nil {
+		return r.Error
+	}
+	if result == nil || len(r.Result) == 0 {
+		return nil
+	}
+	return json.Unmarshal(r.Result, result)
+}
@@ -0,0 +1,214 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/textileio/near-api-go/util"
+)
+
+var ctx = context.Background()
+
+type testRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+func echoServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		respond := func(req testRequest) map[string]interface{} {
+			res := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+			if req.Method == "fail" {
+				res["error"] = map[string]interface{}{
+					"code":    -32000,
+					"message": "Server error",
+					"data":    map[string]interface{}{"reason": "failed"},
+				}
+				return res
+			}
+			res["result"] = map[string]interface{}{
+				"method": req.Method,
+				"params": req.Params,
+				"key":    r.Header.Get("X-Api-Key"),
+			}
+			return res
+		}
+		var raw json.RawMessage
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&raw))
+		if raw[0] == '[' {
+			var reqs []testRequest
+			require.NoError(t, json.Unmarshal(raw, &reqs))
+			var res []interface{}
+			// Respond in reverse order to check responses are matched by id.
+			for i := len(reqs) - 1; i >= 0; i-- {
+				res = append(res, respond(reqs[i]))
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(res))
+			return
+		}
+		var req testRequest
+		require.NoError(t, json.Unmarshal(raw, &req))
+		require.NoError(t, json.NewEncoder(w).Encode(respond(req)))
+	}))
+}
+
+type echo struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Key    string          `json:"key"`
+}
+
+func TestCallContext(t *testing.T) {
+	server := echoServer(t)
+	defer server.Close()
+	c, err := DialHTTP(server.URL, WithHeader("X-Api-Key", "secret"))
+	require.NoError(t, err)
+	defer c.Close()
+
+	var res echo
+	require.NoError(t, c.CallContext(ctx, &res, "status"))
+	require.Equal(t, "status", res.Method)
+	require.JSONEq(t, `[]`, string(res.Params))
+	require.Equal(t, "secret", res.Key)
+
+	require.NoError(t, c.CallContext(ctx, &res, "broadcast_tx_commit", "tx"))
+	require.JSONEq(t, `["tx"]`, string(res.Params))
+
+	require.NoError(t, c.CallContext(ctx, &res, "block", NewNamedParams(map[string]string{"finality": "final"})))
+	require.JSONEq(t, `{"finality": "final"}`, string(res.Params))
+
+	err = c.CallContext(ctx, &res, "fail")
+	var rpcErr *Error
+	require.ErrorAs(t, err, &rpcErr)
+	require.Equal(t, -32000, rpcErr.ErrorCode())
+	require.Equal(t, "Server error", rpcErr.Error())
+	require.Equal(t, map[string]interface{}{"reason": "failed"}, rpcErr.ErrorData())
+}
+
+func TestBatchCallContext(t *testing.T) {
+	server := echoServer(t)
+	defer server.Close()
+	c, err := DialHTTP(server.URL)
+	require.NoError(t, err)
+	defer c.Close()
+
+	var res1, res2 echo
+	elems := []BatchElem{
+		{Method: "block", Args: []interface{}{NewNamedParams(map[string]int{"block_id": 1})}, Result: &res1},
+		{Method: "fail"},
+		{Method: "status", Result: &res2},
+	}
+	require.NoError(t, c.BatchCallContext(ctx, elems))
+	require.NoError(t, elems[0].Error)
+	require.Equal(t, "block", res1.Method)
+	require.JSONEq(t, `{"block_id": 1}`, string(res1.Params))
+	require.Error(t, elems[1].Error)
+	require.NoError(t, elems[2].Error)
+	require.Equal(t, "status", res2.Method)
+}
+
+func TestHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	c, err := DialHTTP(server.URL)
+	require.NoError(t, err)
+
+	err = c.CallContext(ctx, nil, "status")
+	var httpErr *HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusTooManyRequests, httpErr.StatusCode)
+	require.Equal(t, "429 Too Many Requests", err.Error())
+	require.Equal(t, "slow down\n", string(httpErr.Body))
+}
+
+func TestTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+	c, err := DialHTTP(server.URL, WithTimeout(50*time.Millisecond))
+	require.NoError(t, err)
+
+	err = c.CallContext(ctx, nil, "status")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = DialHTTP("ws://localhost")
+	require.Error(t, err)
+}
+
+func TestTokenProvider(t *testing.T) {
+	var auths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auths = append(auths, r.Header.Get("Authorization"))
+		msg := "invalid token " + r.Header.Get("Authorization") + " and key " + r.Header.Get("X-Api-Key")
+		http.Error(w, msg, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	n := 0
+	c, err := DialHTTP(
+		server.URL,
+		WithSecretHeader("X-Api-Key", "api-key-1"),
+		WithTokenProvider(func(context.Context) (string, error) {
+			n++
+			return fmt.Sprintf("token-%d", (n+1)/2), nil
+		}),
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 4; i++ {
+		err = c.CallContext(ctx, nil, "status")
+		var httpErr *HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		require.Equal(t, "invalid token Bearer [REDACTED] and key [REDACTED]\n", string(httpErr.Body))
+	}
+	require.Equal(t, []string{"Bearer token-1", "Bearer token-1", "Bearer token-2", "Bearer token-2"}, auths)
+	require.Equal(t, "[REDACTED] [REDACTED]", util.Redact("token-1 token-2"))
+
+	c.Close()
+	require.Equal(t, "token-1 token-2 api-key-1", util.Redact("token-1 token-2 api-key-1"))
+}
+
+func TestRedactURL(t *testing.T) {
+	c, err := DialHTTP("http://127.0.0.1:1/?apikey=url-api-key")
+	require.NoError(t, err)
+	defer c.Close()
+	err = c.CallContext(ctx, nil, "status")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "apikey=[REDACTED]")
+	require.NotContains(t, err.Error(), "url-api-key")
+}
+
+type closeCountingTransport struct {
+	http.RoundTripper
+	closed int
+}
+
+func (t *closeCountingTransport) CloseIdleConnections() {
+	t.closed++
+}
+
+func TestCloseProvidedHTTPClient(t *testing.T) {
+	transport := &closeCountingTransport{RoundTripper: http.DefaultTransport}
+	c, err := DialHTTP("http://127.0.0.1:1", WithHTTPClient(&http.Client{Transport: transport}))
+	require.NoError(t, err)
+	c.Close()
+	require.Equal(t, 0, transport.closed)
+
+	c, err = DialHTTP("http://127.0.0.1:1")
+	require.NoError(t, err)
+	require.NotSame(t, http.DefaultClient, c.cfg.httpClient)
+	require.NotSame(t, http.DefaultTransport, c.cfg.httpClient.Transport)
+	c.Close()
+}
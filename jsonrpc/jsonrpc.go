@@ -0,0 +1,119 @@
+// Package jsonrpc provides the JSON-RPC transport used to talk to NEAR nodes.
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Client sends JSON-RPC requests to a NEAR node.
+type Client interface {
+	// CallContext calls a RPC method and unmarshals the result into result, which must be
+	// a pointer or nil. The request params are args as an array, or the value of a single
+	// NamedParams arg as an object.
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+	// BatchCallContext sends the elements in a single request. The returned error is only set if
+	// the request as a whole failed. Errors of individual elements are set in their Error field.
+	BatchCallContext(ctx context.Context, b []BatchElem) error
+	// Close releases the resources held by the client.
+	Close()
+}
+
+// BatchElem is an element of a batch request.
+type BatchElem struct {
+	Method string
+	Args   []interface{}
+	// Result is unmarshaled from the response. It must be a pointer or nil.
+	Result interface{}
+	// Error is set if the call failed, or if the response is missing.
+	Error error
+}
+
+// NamedParams wraps a struct or map value to send RPC params as an object.
+type NamedParams struct {
+	Value interface{}
+}
+
+// NewNamedParams creates NamedParams for the provided struct or map value.
+func NewNamedParams(value interface{}) NamedParams {
+	return NamedParams{Value: value}
+}
+
+// Error is an error returned by the node in a JSON-RPC response.
+type Error struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	// Name and Cause are set by nodes returning structured errors, like a HANDLER_ERROR
+	// caused by an UNKNOWN_BLOCK.
+	Name  string      `json:"name,omitempty"`
+	Cause *ErrorCause `json:"cause,omitempty"`
+}
+
+// ErrorCause is the cause of a structured error.
+type ErrorCause struct {
+	Name string          `json:"name"`
+	Info json.RawMessage `json:"info,omitempty"`
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("json-rpc error %d", e.Code)
+	}
+	return e.Message
+}
+
+// ErrorCode returns the JSON-RPC error code.
+func (e *Error) ErrorCode() int {
+	return e.Code
+}
+
+// CauseName returns the name of the cause of a structured error, or "" if there's none.
+func (e *Error) CauseName() string {
+	if e.Cause == nil {
+		return ""
+	}
+	return e.Cause.Name
+}
+
+// ErrorData returns the decoded error data, or nil if there's none.
+func (e *Error) ErrorData() interface{} {
+	if len(e.Data) == 0 {
+		return nil
+	}
+	var data interface{}
+	if err := json.Unmarshal(e.Data, &data); err != nil {
+		return string(e.Data)
+	}
+	return data
+}
+
+// HTTPError is returned when the node responds with a non-2xx HTTP status.
+type HTTPError struct {
+	// StatusCode is the HTTP status code, like 429.
+	StatusCode int
+	// Status is the HTTP status, like "429 Too Many Requests".
+	Status string
+	// Body is the response body.
+	Body []byte
+}
+
+// Error implements error. It returns the HTTP status, so errors can be classified by their message.
+func (e *HTTPError) Error() string {
+	return e.Status
+}
+
+// params encodes RPC args as a JSON array, or as the object of a single NamedParams arg.
+func params(args []interface{}) (json.RawMessage, error) {
+	if len(args) == 1 {
+		if named, ok := args[0].(NamedParams); ok {
+			return json.Marshal(named.Value)
+		}
+	}
+	if args == nil {
+		args = []interface{}{}
+	}
+	return json.Marshal(args)
+}
@@ -0,0 +1,287 @@
+// Package mocks provides fakes of the client interfaces for unit tests.
+//
+// Each fake method records the call and delegates to the matching func field,
+// returning an error if the field is nil. Options are recorded as the request they
+// build, so tests can assert on the block reference or args of a call.
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	api "github.com/textileio/near-api-go"
+	"github.com/textileio/near-api-go/account"
+	itypes "github.com/textileio/near-api-go/internal/types"
+	"github.com/textileio/near-api-go/keys"
+	"github.com/textileio/near-api-go/transaction"
+)
+
+// notImplemented returns the error of a fake method whose func field is nil.
+func notImplemented(method string) error {
+	return fmt.Errorf("mocks: %s not implemented", method)
+}
+
+// QueryRequest is the query built by the options of a CallFunction, ViewCode, State,
+// ViewState or ViewAccessKey call. Only the request type, the options, and the account id
+// and method name arguments are set, not the defaults of the real methods.
+type QueryRequest = itypes.QueryRequest
+
+// ChangesRequest is the request built by the options of a DataChanges call, without the
+// defaults of the real method.
+type ChangesRequest = itypes.ChangesRequest
+
+// Call is a recorded method call.
+type Call struct {
+	Method string
+	// Args are the arguments of the call, excluding the context. Options are replaced by
+	// the value they build: a QueryRequest, a ChangesRequest, or the transaction.FunctionCall
+	// of FunctionCall.
+	Args []interface{}
+}
+
+type recorder struct {
+	lk    sync.Mutex
+	calls []Call
+}
+
+func (r *recorder) record(method string, args ...interface{}) {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns the recorded calls in order.
+func (r *recorder) Calls() []Call {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	res := make([]Call, len(r.calls))
+	copy(res, r.calls)
+	return res
+}
+
+// CallsTo returns the recorded calls of a method in order.
+func (r *recorder) CallsTo(method string) []Call {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	var res []Call
+	for _, c := range r.calls {
+		if c.Method == method {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// Viewer is a fake api.Viewer.
+type Viewer struct {
+	recorder
+
+	CallFunctionFunc func(
+		ctx context.Context,
+		accountID string,
+		methodName string,
+		opts ...api.CallFunctionOption,
+	) (*api.CallFunctionResponse, error)
+	ViewCodeFunc func(
+		ctx context.Context,
+		accountID string,
+		opts ...api.ViewCodeOption,
+	) (*api.ViewCodeResponse, error)
+	DataChangesFunc func(
+		ctx context.Context,
+		accountIDs []string,
+		opts ...api.DataChangesOption,
+	) (*api.DataChangesResponse, error)
+	NodeStatusFunc func(ctx context.Context) (*api.NodeStatusResponse, error)
+}
+
+var _ api.Viewer = (*Viewer)(nil)
+
+// CallFunction implements api.Viewer.
+func (v *Viewer) CallFunction(
+	ctx context.Context,
+	accountID string,
+	methodName string,
+	opts ...api.CallFunctionOption,
+) (*api.CallFunctionResponse, error) {
+	req := QueryRequest{RequestType: "call_function", AccountID: accountID, MethodName: methodName}
+	for _, opt := range opts {
+		if err := opt(&req); err != nil {
+			return nil, err
+		}
+	}
+	v.record("CallFunction", accountID, methodName, req)
+	if v.CallFunctionFunc == nil {
+		return nil, notImplemented("CallFunction")
+	}
+	return v.CallFunctionFunc(ctx, accountID, methodName, opts...)
+}
+
+// ViewCode implements api.Viewer.
+func (v *Viewer) ViewCode(
+	ctx context.Context,
+	accountID string,
+	opts ...api.ViewCodeOption,
+) (*api.ViewCodeResponse, error) {
+	req := QueryRequest{RequestType: "view_code", AccountID: accountID}
+	for _, opt := range opts {
+		opt(&req)
+	}
+	v.record("ViewCode", accountID, req)
+	if v.ViewCodeFunc == nil {
+		return nil, notImplemented("ViewCode")
+	}
+	return v.ViewCodeFunc(ctx, accountID, opts...)
+}
+
+// DataChanges implements api.Viewer.
+func (v *Viewer) DataChanges(
+	ctx context.Context,
+	accountIDs []string,
+	opts ...api.DataChangesOption,
+) (*api.DataChangesResponse, error) {
+	req := ChangesRequest{ChangesType: "data_changes", AccountIDs: accountIDs}
+	for _, opt := range opts {
+		opt(&req)
+	}
+	v.record("DataChanges", accountIDs, req)
+	if v.DataChangesFunc == nil {
+		return nil, notImplemented("DataChanges")
+	}
+	return v.DataChangesFunc(ctx, accountIDs, opts...)
+}
+
+// NodeStatus implements api.Viewer.
+func (v *Viewer) NodeStatus(ctx context.Context) (*api.NodeStatusResponse, error) {
+	v.record("NodeStatus")
+	if v.NodeStatusFunc == nil {
+		return nil, notImplemented("NodeStatus")
+	}
+	return v.NodeStatusFunc(ctx)
+}
+
+// Account is a fake account.AccountAPI, which also implements account.Viewer and account.Sender.
+type Account struct {
+	recorder
+
+	StateFunc         func(ctx context.Context, opts ...account.StateOption) (*account.AccountView, error)
+	ViewStateFunc     func(ctx context.Context, opts ...account.ViewStateOption) (*account.AccountStateView, error)
+	ViewAccessKeyFunc func(
+		ctx context.Context,
+		pubKey *keys.PublicKey,
+		opts ...account.ViewAccessKeyOption,
+	) (*account.AccessKeyView, error)
+	SignAndSendTransactionFunc func(
+		ctx context.Context,
+		receiverID string,
+		actions ...transaction.Action,
+	) (*account.FinalExecutionOutcome, error)
+	FunctionCallFunc func(
+		ctx context.Context,
+		contractID,
+		methodName string,
+		opts ...transaction.FunctionCallOpton,
+	) (*account.FinalExecutionOutcome, error)
+	DeployContractFunc func(ctx context.Context, code []byte) (*account.FinalExecutionOutcome, error)
+	SendMoneyFunc      func(ctx context.Context, receiverID string, amount big.Int) (*account.FinalExecutionOutcome, error)
+}
+
+var _ account.AccountAPI = (*Account)(nil)
+
+// State implements account.Viewer.
+func (a *Account) State(ctx context.Context, opts ...account.StateOption) (*account.AccountView, error) {
+	req := QueryRequest{RequestType: "view_account"}
+	for _, opt := range opts {
+		opt(&req)
+	}
+	a.record("State", req)
+	if a.StateFunc == nil {
+		return nil, notImplemented("State")
+	}
+	return a.StateFunc(ctx, opts...)
+}
+
+// ViewState implements account.Viewer.
+func (a *Account) ViewState(ctx context.Context, opts ...account.ViewStateOption) (*account.AccountStateView, error) {
+	req := QueryRequest{RequestType: "view_state"}
+	for _, opt := range opts {
+		opt(&req)
+	}
+	a.record("ViewState", req)
+	if a.ViewStateFunc == nil {
+		return nil, notImplemented("ViewState")
+	}
+	return a.ViewStateFunc(ctx, opts...)
+}
+
+// ViewAccessKey implements account.Viewer.
+func (a *Account) ViewAccessKey(
+	ctx context.Context,
+	pubKey *keys.PublicKey,
+	opts ...account.ViewAccessKeyOption,
+) (*account.AccessKeyView, error) {
+	req := QueryRequest{RequestType: "view_access_key"}
+	for _, opt := range opts {
+		opt(&req)
+	}
+	a.record("ViewAccessKey", pubKey, req)
+	if a.ViewAccessKeyFunc == nil {
+		return nil, notImplemented("ViewAccessKey")
+	}
+	return a.ViewAccessKeyFunc(ctx, pubKey, opts...)
+}
+
+// SignAndSendTransaction implements account.Sender.
+func (a *Account) SignAndSendTransaction(
+	ctx context.Context,
+	receiverID string,
+	actions ...transaction.Action,
+) (*account.FinalExecutionOutcome, error) {
+	a.record("SignAndSendTransaction", receiverID, actions)
+	if a.SignAndSendTransactionFunc == nil {
+		return nil, notImplemented("SignAndSendTransaction")
+	}
+	return a.SignAndSendTransactionFunc(ctx, receiverID, actions...)
+}
+
+// FunctionCall implements account.Sender.
+func (a *Account) FunctionCall(
+	ctx context.Context,
+	contractID,
+	methodName string,
+	opts ...transaction.FunctionCallOpton,
+) (*account.FinalExecutionOutcome, error) {
+	action, err := transaction.FunctionCallAction(methodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	a.record("FunctionCall", contractID, methodName, action.FunctionCall)
+	if a.FunctionCallFunc == nil {
+		return nil, notImplemented("FunctionCall")
+	}
+	return a.FunctionCallFunc(ctx, contractID, methodName, opts...)
+}
+
+// DeployContract implements account.Sender.
+func (a *Account) DeployContract(ctx context.Context, code []byte) (*account.FinalExecutionOutcome, error) {
+	a.record("DeployContract", code)
+	if a.DeployContractFunc == nil {
+		return nil, notImplemented("DeployContract")
+	}
+	return a.DeployContractFunc(ctx, code)
+}
+
+// SendMoney implements account.Sender.
+func (a *Account) SendMoney(
+	ctx context.Context,
+	receiverID string,
+	amount big.Int,
+) (*account.FinalExecutionOutcome, error) {
+	a.record("SendMoney", receiverID, amount)
+	if a.SendMoneyFunc == nil {
+		return nil, notImplemented("SendMoney")
+	}
+	return a.SendMoneyFunc(ctx, receiverID, amount)
+}
@@ -0,0 +1,86 @@
+package mocks
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	api "github.com/textileio/near-api-go"
+	"github.com/textileio/near-api-go/account"
+	"github.com/textileio/near-api-go/transaction"
+	"github.com/textileio/near-api-go/types"
+)
+
+// balance is an example of code depending on the interfaces rather than the concrete clients.
+func balance(ctx context.Context, v api.Viewer, a account.Viewer) (string, error) {
+	if _, err := v.NodeStatus(ctx); err != nil {
+		return "", err
+	}
+	state, err := a.State(ctx, account.StateWithFinality("final"))
+	if err != nil {
+		return "", err
+	}
+	return state.Amount, nil
+}
+
+func TestFakes(t *testing.T) {
+	ctx := context.Background()
+	v := &Viewer{
+		NodeStatusFunc: func(context.Context) (*api.NodeStatusResponse, error) {
+			return &api.NodeStatusResponse{}, nil
+		},
+	}
+	a := &Account{
+		StateFunc: func(context.Context, ...account.StateOption) (*account.AccountView, error) {
+			return &account.AccountView{Amount: "100"}, nil
+		},
+	}
+	amount, err := balance(ctx, v, a)
+	require.NoError(t, err)
+	require.Equal(t, "100", amount)
+	require.Len(t, v.CallsTo("NodeStatus"), 1)
+	require.Equal(t, []Call{{Method: "State", Args: []interface{}{QueryRequest{
+		RequestType: "view_account",
+		Finality:    "final",
+	}}}}, a.Calls())
+
+	a.StateFunc = func(context.Context, ...account.StateOption) (*account.AccountView, error) {
+		return nil, errors.New("boom")
+	}
+	_, err = balance(ctx, v, a)
+	require.EqualError(t, err, "boom")
+
+	res, err := a.FunctionCall(ctx, "contract.test", "method")
+	require.EqualError(t, err, "mocks: FunctionCall not implemented")
+	require.Nil(t, res)
+	args := a.CallsTo("FunctionCall")[0].Args
+	require.Equal(t, []interface{}{"contract.test", "method"}, args[:2])
+	require.Equal(t, "method", args[2].(transaction.FunctionCall).MethodName)
+	_, err = a.SendMoney(ctx, "bob.test", *big.NewInt(1))
+	require.EqualError(t, err, "mocks: SendMoney not implemented")
+}
+
+func TestRecordedOptions(t *testing.T) {
+	ctx := context.Background()
+	v := &Viewer{}
+	_, err := v.CallFunction(
+		ctx,
+		"contract.test",
+		"method",
+		api.CallFunctionWithArgs(map[string]int{"a": 1}),
+		api.CallFunctionWithBlockReference(types.BlockHeight(5)),
+	)
+	require.Error(t, err)
+	req := v.CallsTo("CallFunction")[0].Args[2].(QueryRequest)
+	require.Equal(t, "eyJhIjoxfQ==", req.ArgsBase64)
+	require.Equal(t, 5, req.BlockID)
+	require.Empty(t, req.Finality)
+
+	_, err = v.DataChanges(ctx, []string{"contract.test"}, api.DataChangesWithFinality("final"))
+	require.Error(t, err)
+	changes := v.CallsTo("DataChanges")[0].Args[1].(ChangesRequest)
+	require.Equal(t, "final", changes.Finality)
+	require.Equal(t, []string{"contract.test"}, changes.AccountIDs)
+}
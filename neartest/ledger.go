@@ -0,0 +1,461 @@
+package neartest
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/mr-tron/base58/base58"
+	"github.com/textileio/near-api-go/transaction"
+	"github.com/textileio/near-api-go/types"
+)
+
+// Call is passed to a CallFunc with the details of a function call action.
+type Call struct {
+	SignerID      string
+	PredecessorID string
+	ContractID    string
+	MethodName    string
+	Args          []byte
+	Deposit       *big.Int
+
+	state   map[string][]byte
+	logs    []string
+	changes []change
+}
+
+// Get returns the value stored under the key in the contract state.
+func (c *Call) Get(key []byte) ([]byte, bool) {
+	v, ok := c.state[string(key)]
+	return v, ok
+}
+
+// Set stores the value under the key in the contract state.
+func (c *Call) Set(key, value []byte) {
+	c.state[string(key)] = value
+	c.changes = append(c.changes, change{accountID: c.ContractID, key: key, value: value})
+}
+
+// Delete removes the key from the contract state.
+func (c *Call) Delete(key []byte) {
+	delete(c.state, string(key))
+	c.changes = append(c.changes, change{accountID: c.ContractID, key: key})
+}
+
+// Log adds a log line to the receipt outcome.
+func (c *Call) Log(line string) {
+	c.logs = append(c.logs, line)
+}
+
+// View is passed to a ViewFunc with the details of a call_function query.
+type View struct {
+	ContractID string
+	MethodName string
+	Args       []byte
+
+	state map[string][]byte
+	logs  []string
+}
+
+// Get returns the value stored under the key in the contract state.
+func (v *View) Get(key []byte) ([]byte, bool) {
+	value, ok := v.state[string(key)]
+	return value, ok
+}
+
+// Log adds a log line to the query result.
+func (v *View) Log(line string) {
+	v.logs = append(v.logs, line)
+}
+
+// CallFunc handles a function call action. The returned bytes are the SuccessValue of the receipt.
+// Returning an error fails the receipt and reverts its state changes.
+type CallFunc func(call *Call) ([]byte, error)
+
+// ViewFunc handles a call_function query. The returned bytes are the query result.
+type ViewFunc func(view *View) ([]byte, error)
+
+type accessKey struct {
+	nonce      uint64
+	permission transaction.AccessKeyPermission
+}
+
+type account struct {
+	amount     *big.Int
+	locked     *big.Int
+	code       []byte
+	state      map[string][]byte
+	accessKeys map[string]*accessKey
+}
+
+func newAccount(amount *big.Int) *account {
+	return &account{
+		amount:     new(big.Int).Set(amount),
+		locked:     new(big.Int),
+		state:      make(map[string][]byte),
+		accessKeys: make(map[string]*accessKey),
+	}
+}
+
+func (a *account) clone() *account {
+	c := &account{
+		amount:     new(big.Int).Set(a.amount),
+		locked:     new(big.Int).Set(a.locked),
+		code:       a.code,
+		state:      make(map[string][]byte, len(a.state)),
+		accessKeys: make(map[string]*accessKey, len(a.accessKeys)),
+	}
+	for k, v := range a.state {
+		c.state[k] = v
+	}
+	for k, v := range a.accessKeys {
+		key := *v
+		c.accessKeys[k] = &key
+	}
+	return c
+}
+
+func (a *account) codeHash() string {
+	if len(a.code) == 0 {
+		return "11111111111111111111111111111111"
+	}
+	h := sha256.Sum256(a.code)
+	return base58.Encode(h[:])
+}
+
+func (a *account) storageUsage() int {
+	usage := 100 + len(a.code)
+	for k, v := range a.state {
+		usage += 40 + len(k) + len(v)
+	}
+	return usage + 82*len(a.accessKeys)
+}
+
+type block struct {
+	height    int
+	hash      string
+	prevHash  string
+	timestamp uint64
+	changes   []change
+}
+
+type change struct {
+	accountID   string
+	key         []byte
+	value       []byte // nil for deletions.
+	receiptHash string
+}
+
+func newBlock(height int, prevHash string, timestamp uint64) *block {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, uint64(height))
+	h := sha256.Sum256(append(buf, prevHash...))
+	return &block{
+		height:    height,
+		hash:      base58.Encode(h[:]),
+		prevHash:  prevHash,
+		timestamp: timestamp,
+	}
+}
+
+// actionError fails a receipt.
+type actionError struct {
+	index int
+	kind  interface{}
+}
+
+func (e *actionError) Error() string {
+	return fmt.Sprintf("action #%d failed: %v", e.index, e.kind)
+}
+
+// txError rejects a transaction before it's executed.
+type txError struct {
+	data interface{}
+}
+
+func (e *txError) Error() string {
+	return fmt.Sprintf("invalid transaction: %v", e.data)
+}
+
+func invalidTx(name string, details interface{}) *txError {
+	return &txError{data: map[string]interface{}{
+		"TxExecutionError": map[string]interface{}{
+			"InvalidTxError": map[string]interface{}{name: details},
+		},
+	}}
+}
+
+type outcome struct {
+	value []byte
+	logs  []string
+	err   *actionError
+}
+
+// execute applies the actions of a receipt to the accounts. The accounts are only
+// changed if all actions succeed.
+func (s *Server) execute(tx transaction.Transaction, receiptHash string) (*outcome, []change) {
+	accounts := make(map[string]*account)
+	get := func(id string) *account {
+		if a, ok := accounts[id]; ok {
+			return a
+		}
+		a, ok := s.accounts[id]
+		if !ok {
+			return nil
+		}
+		accounts[id] = a.clone()
+		return accounts[id]
+	}
+	deleted := make(map[string]bool)
+	res := &outcome{}
+	var changes []change
+
+	fail := func(i int, kind interface{}) (*outcome, []change) {
+		return &outcome{logs: res.logs, err: &actionError{index: i, kind: kind}}, nil
+	}
+	for i, action := range tx.Actions {
+		receiver := get(tx.ReceiverID)
+		if receiver == nil && action.Enum == 3 && types.AccountID(tx.ReceiverID).IsImplicit() {
+			// Transfers create implicit accounts, with the key of the id as full access key.
+			data, _ := hex.DecodeString(tx.ReceiverID)
+			receiver = newAccount(new(big.Int))
+			receiver.accessKeys["ed25519:"+base58.Encode(data)] = &accessKey{
+				permission: transaction.AccessKeyPermission{Enum: 1},
+			}
+			accounts[tx.ReceiverID] = receiver
+			delete(deleted, tx.ReceiverID)
+		}
+		if receiver == nil && action.Enum != 0 {
+			return fail(i, map[string]interface{}{
+				"AccountDoesNotExist": map[string]interface{}{"account_id": tx.ReceiverID},
+			})
+		}
+		switch action.Enum {
+		case 0:
+			if receiver != nil {
+				return fail(i, map[string]interface{}{
+					"AccountAlreadyExists": map[string]interface{}{"account_id": tx.ReceiverID},
+				})
+			}
+			if !strings.HasSuffix(tx.ReceiverID, "."+tx.SignerID) && strings.Contains(tx.ReceiverID, ".") {
+				return fail(i, map[string]interface{}{
+					"CreateAccountNotAllowed": map[string]interface{}{
+						"account_id":     tx.ReceiverID,
+						"predecessor_id": tx.SignerID,
+					},
+				})
+			}
+			accounts[tx.ReceiverID] = newAccount(new(big.Int))
+			delete(deleted, tx.ReceiverID)
+		case 1:
+			receiver.code = action.DeployContract.Code
+		case 2:
+			fc := action.FunctionCall
+			deposit := new(big.Int).Set(&fc.Deposit)
+			get(tx.SignerID).amount.Sub(get(tx.SignerID).amount, deposit)
+			receiver.amount.Add(receiver.amount, deposit)
+			handler, ok := s.calls[tx.ReceiverID+"/"+fc.MethodName]
+			if !ok {
+				return fail(i, map[string]interface{}{
+					"FunctionCallError": map[string]interface{}{"MethodResolveError": "MethodNotFound"},
+				})
+			}
+			call := &Call{
+				SignerID:      tx.SignerID,
+				PredecessorID: tx.SignerID,
+				ContractID:    tx.ReceiverID,
+				MethodName:    fc.MethodName,
+				Args:          fc.Args,
+				Deposit:       deposit,
+				state:         receiver.state,
+			}
+			value, err := handler(call)
+			res.logs = append(res.logs, call.logs...)
+			if err != nil {
+				return fail(i, map[string]interface{}{
+					"FunctionCallError": map[string]interface{}{
+						"ExecutionError": "Smart contract panicked: " + err.Error(),
+					},
+				})
+			}
+			for _, c := range call.changes {
+				c.receiptHash = receiptHash
+				changes = append(changes, c)
+			}
+			res.value = value
+		case 3:
+			amount := &action.Transfer.Deposit
+			get(tx.SignerID).amount.Sub(get(tx.SignerID).amount, amount)
+			receiver.amount.Add(receiver.amount, amount)
+		case 4:
+			stake := &action.Stake.Stake
+			total := new(big.Int).Add(receiver.amount, receiver.locked)
+			if total.Cmp(stake) < 0 {
+				return fail(i, map[string]interface{}{
+					"TriesToStake": map[string]interface{}{"account_id": tx.ReceiverID, "stake": stake.String()},
+				})
+			}
+			receiver.amount = total.Sub(total, stake)
+			receiver.locked = new(big.Int).Set(stake)
+		case 5:
+			pk := publicKeyString(action.AddKey.PublicKey)
+			if _, ok := receiver.accessKeys[pk]; ok {
+				return fail(i, map[string]interface{}{
+					"AddKeyAlreadyExists": map[string]interface{}{"account_id": tx.ReceiverID, "public_key": pk},
+				})
+			}
+			receiver.accessKeys[pk] = &accessKey{
+				nonce:      action.AddKey.AccessKey.Nonce,
+				permission: action.AddKey.AccessKey.Permission,
+			}
+		case 6:
+			pk := publicKeyString(action.DeleteKey.PublicKey)
+			if _, ok := receiver.accessKeys[pk]; !ok {
+				return fail(i, map[string]interface{}{
+					"DeleteKeyDoesNotExist": map[string]interface{}{"account_id": tx.ReceiverID, "public_key": pk},
+				})
+			}
+			delete(receiver.accessKeys, pk)
+		case 7:
+			beneficiary := get(action.DeleteAccount.BeneficiaryID)
+			if beneficiary != nil {
+				beneficiary.amount.Add(beneficiary.amount, receiver.amount)
+			}
+			delete(accounts, tx.ReceiverID)
+			deleted[tx.ReceiverID] = true
+		}
+	}
+
+	for id, a := range accounts {
+		s.accounts[id] = a
+	}
+	for id := range deleted {
+		delete(s.accounts, id)
+	}
+	return res, changes
+}
+
+// totalCost returns the amount transferred out of the signer by the actions.
+func totalCost(actions []transaction.Action) *big.Int {
+	total := new(big.Int)
+	for _, action := range actions {
+		switch action.Enum {
+		case 2:
+			total.Add(total, &action.FunctionCall.Deposit)
+		case 3:
+			total.Add(total, &action.Transfer.Deposit)
+		}
+	}
+	return total
+}
+
+// checkPermission returns an error if the access key isn't allowed to sign the transaction.
+func checkPermission(key *accessKey, tx transaction.Transaction) *txError {
+	if key.permission.Enum == 1 {
+		return nil
+	}
+	fc := key.permission.FunctionCall
+	if tx.ReceiverID != fc.ReceiverID {
+		return invalidTx("InvalidAccessKeyError", map[string]interface{}{
+			"ReceiverMismatch": map[string]interface{}{"tx_receiver": tx.ReceiverID, "ak_receiver": fc.ReceiverID},
+		})
+	}
+	for _, action := range tx.Actions {
+		if action.Enum != 2 || action.FunctionCall.Deposit.Sign() != 0 {
+			return invalidTx("InvalidAccessKeyError", "RequiresFullAccess")
+		}
+		if len(fc.MethodNames) == 0 {
+			continue
+		}
+		allowed := false
+		for _, m := range fc.MethodNames {
+			allowed = allowed || m == action.FunctionCall.MethodName
+		}
+		if !allowed {
+			return invalidTx("InvalidAccessKeyError", map[string]interface{}{
+				"MethodNameMismatch": map[string]interface{}{"method_name": action.FunctionCall.MethodName},
+			})
+		}
+	}
+	return nil
+}
+
+func publicKeyString(pk transaction.PublicKey) string {
+	return "ed25519:" + base58.Encode(pk.Data[:])
+}
+
+func permissionJSON(p transaction.AccessKeyPermission) interface{} {
+	if p.Enum == 1 {
+		return "FullAccess"
+	}
+	var allowance interface{}
+	if p.FunctionCall.Allowance != nil {
+		allowance = p.FunctionCall.Allowance.String()
+	}
+	methodNames := p.FunctionCall.MethodNames
+	if methodNames == nil {
+		methodNames = []string{}
+	}
+	return map[string]interface{}{
+		"FunctionCall": map[string]interface{}{
+			"allowance":    allowance,
+			"receiver_id":  p.FunctionCall.ReceiverID,
+			"method_names": methodNames,
+		},
+	}
+}
+
+func actionJSON(a transaction.Action) interface{} {
+	switch a.Enum {
+	case 0:
+		return "CreateAccount"
+	case 1:
+		return map[string]interface{}{"DeployContract": map[string]interface{}{
+			"code": base64.StdEncoding.EncodeToString(a.DeployContract.Code),
+		}}
+	case 2:
+		return map[string]interface{}{"FunctionCall": map[string]interface{}{
+			"method_name": a.FunctionCall.MethodName,
+			"args":        base64.StdEncoding.EncodeToString(a.FunctionCall.Args),
+			"gas":         a.FunctionCall.Gas,
+			"deposit":     a.FunctionCall.Deposit.String(),
+		}}
+	case 3:
+		return map[string]interface{}{"Transfer": map[string]interface{}{"deposit": a.Transfer.Deposit.String()}}
+	case 4:
+		return map[string]interface{}{"Stake": map[string]interface{}{
+			"stake":      a.Stake.Stake.String(),
+			"public_key": publicKeyString(a.Stake.PublicKey),
+		}}
+	case 5:
+		return map[string]interface{}{"AddKey": map[string]interface{}{
+			"public_key": publicKeyString(a.AddKey.PublicKey),
+			"access_key": map[string]interface{}{
+				"nonce":      a.AddKey.AccessKey.Nonce,
+				"permission": permissionJSON(a.AddKey.AccessKey.Permission),
+			},
+		}}
+	case 6:
+		return map[string]interface{}{"DeleteKey": map[string]interface{}{
+			"public_key": publicKeyString(a.DeleteKey.PublicKey),
+		}}
+	default:
+		return map[string]interface{}{"DeleteAccount": map[string]interface{}{
+			"beneficiary_id": a.DeleteAccount.BeneficiaryID,
+		}}
+	}
+}
+
+func sortedStateKeys(state map[string][]byte) []string {
+	keys := make([]string, 0, len(state))
+	for k := range state {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,674 @@
+package neartest
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mr-tron/base58/base58"
+	"github.com/near/borsh-go"
+	"github.com/textileio/near-api-go/jsonrpc"
+	"github.com/textileio/near-api-go/keys"
+	"github.com/textileio/near-api-go/transaction"
+	"github.com/textileio/near-api-go/types"
+)
+
+// Server is an in-process NEAR JSON-RPC server backed by an in-memory ledger.
+//
+// It implements query (view_account, view_access_key, view_state, call_function and view_code),
+// block, status, EXPERIMENTAL_changes (data_changes) and broadcast_tx_commit. Every transaction
+// is included in a new block, and every block is final. Queries are answered from the latest
+// state, whichever block they reference, as long as the block exists.
+type Server struct {
+	// URL is the url of the server.
+	URL string
+
+	server  *httptest.Server
+	chainID string
+
+	lk       sync.Mutex
+	blocks   []*block
+	accounts map[string]*account
+	views    map[string]ViewFunc
+	calls    map[string]CallFunc
+}
+
+// Option controls the behavior of a Server.
+type Option func(*Server)
+
+// WithChainID specifies the chain id reported by status. Defaults to "neartest".
+func WithChainID(chainID string) Option {
+	return func(s *Server) {
+		s.chainID = chainID
+	}
+}
+
+// NewServer starts a Server. It must be closed with Close.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		chainID:  "neartest",
+		accounts: make(map[string]*account),
+		views:    make(map[string]ViewFunc),
+		calls:    make(map[string]CallFunc),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.blocks = []*block{newBlock(1, "11111111111111111111111111111111", uint64(time.Now().UnixNano()))}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Client creates a RPC client connected to the server.
+func (s *Server) Client() (*jsonrpc.HTTPClient, error) {
+	return jsonrpc.DialHTTP(s.URL)
+}
+
+// Config creates a Config with a RPC client connected to the server, using the provided signer.
+func (s *Server) Config(signer keys.KeyPair) (*types.Config, error) {
+	client, err := s.Client()
+	if err != nil {
+		return nil, fmt.Errorf("creating rpc client: %v", err)
+	}
+	return &types.Config{
+		RPCClient: client,
+		Signer:    signer,
+		NetworkID: s.chainID,
+	}, nil
+}
+
+// AddAccount creates an account with the provided balance.
+func (s *Server) AddAccount(accountID string, amount *big.Int) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	s.accounts[accountID] = newAccount(amount)
+}
+
+// AddAccessKey adds a full access key to an account. It panics if the account doesn't exist.
+func (s *Server) AddAccessKey(accountID string, pubKey keys.PublicKey) {
+	s.AddFunctionCallAccessKey(accountID, pubKey, nil)
+}
+
+// AddFunctionCallAccessKey adds an access key to an account. The key has full access if permission is nil.
+// It panics if the account doesn't exist.
+func (s *Server) AddFunctionCallAccessKey(
+	accountID string,
+	pubKey keys.PublicKey,
+	permission *transaction.FunctionCallPermission,
+) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	key := &accessKey{permission: transaction.AccessKeyPermission{Enum: 1}}
+	if permission != nil {
+		key.permission = transaction.AccessKeyPermission{Enum: 0, FunctionCall: *permission}
+	}
+	pk, err := pubKey.ToString()
+	if err != nil {
+		panic(err)
+	}
+	s.mustAccount(accountID).accessKeys[pk] = key
+}
+
+// SetCode sets the contract code of an account. It panics if the account doesn't exist.
+func (s *Server) SetCode(accountID string, code []byte) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	s.mustAccount(accountID).code = code
+}
+
+// SetState stores the value under the key in the contract state of an account.
+// It panics if the account doesn't exist.
+func (s *Server) SetState(accountID string, key, value []byte) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	s.mustAccount(accountID).state[string(key)] = value
+}
+
+// Balance returns the balance of an account, or nil if it doesn't exist.
+func (s *Server) Balance(accountID string) *big.Int {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	a, ok := s.accounts[accountID]
+	if !ok {
+		return nil
+	}
+	return new(big.Int).Set(a.amount)
+}
+
+// Nonce returns the nonce of an access key, and whether the key exists.
+func (s *Server) Nonce(accountID string, pubKey keys.PublicKey) (uint64, bool) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	pk, err := pubKey.ToString()
+	if err != nil {
+		return 0, false
+	}
+	a, ok := s.accounts[accountID]
+	if !ok {
+		return 0, false
+	}
+	key, ok := a.accessKeys[pk]
+	if !ok {
+		return 0, false
+	}
+	return key.nonce, true
+}
+
+// RegisterView registers a handler for call_function queries of a contract method.
+func (s *Server) RegisterView(contractID, methodName string, fn ViewFunc) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	s.views[contractID+"/"+methodName] = fn
+}
+
+// RegisterCall registers a handler for function call actions of a contract method.
+func (s *Server) RegisterCall(contractID, methodName string, fn CallFunc) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	s.calls[contractID+"/"+methodName] = fn
+}
+
+// ProduceBlock adds an empty block and returns its height.
+func (s *Server) ProduceBlock() int {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	return s.produceBlock(nil).height
+}
+
+// Height returns the height of the latest block.
+func (s *Server) Height() int {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	return s.head().height
+}
+
+func (s *Server) mustAccount(accountID string) *account {
+	a, ok := s.accounts[accountID]
+	if !ok {
+		panic(fmt.Sprintf("account %s doesn't exist", accountID))
+	}
+	return a
+}
+
+func (s *Server) head() *block {
+	return s.blocks[len(s.blocks)-1]
+}
+
+func (s *Server) produceBlock(changes []change) *block {
+	head := s.head()
+	timestamp := uint64(time.Now().UnixNano())
+	if timestamp <= head.timestamp {
+		timestamp = head.timestamp + 1
+	}
+	b := newBlock(head.height+1, head.hash, timestamp)
+	b.changes = changes
+	s.blocks = append(s.blocks, b)
+	return b
+}
+
+type rpcRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type rpcError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data"`
+	Name    string      `json:"name"`
+	Cause   struct {
+		Name string `json:"name"`
+	} `json:"cause"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Cause.Name, e.Data)
+}
+
+func handlerError(cause string, data interface{}) *rpcError {
+	e := &rpcError{Code: -32000, Message: "Server error", Data: data, Name: "HANDLER_ERROR"}
+	e.Cause.Name = cause
+	return e
+}
+
+func requestError(cause string, data interface{}) *rpcError {
+	e := &rpcError{Code: -32602, Message: "Invalid params", Data: data, Name: "REQUEST_VALIDATION_ERROR"}
+	e.Cause.Name = cause
+	return e
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if strings.HasPrefix(strings.TrimSpace(string(raw)), "[") {
+		var reqs []rpcRequest
+		if err := json.Unmarshal(raw, &reqs); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		res := make([]interface{}, len(reqs))
+		for i, req := range reqs {
+			res[i] = s.dispatch(req)
+		}
+		_ = json.NewEncoder(w).Encode(res)
+		return
+	}
+	var req rpcRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(s.dispatch(req))
+}
+
+func (s *Server) dispatch(req rpcRequest) interface{} {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	var result interface{}
+	var err *rpcError
+	switch req.Method {
+	case "query":
+		result, err = s.query(req.Params)
+	case "block":
+		result, err = s.block(req.Params)
+	case "status":
+		result = s.status()
+	case "EXPERIMENTAL_changes":
+		result, err = s.changes(req.Params)
+	case "broadcast_tx_commit":
+		result, err = s.broadcastTxCommit(req.Params)
+	default:
+		err = &rpcError{Code: -32601, Message: "Method not found", Data: req.Method, Name: "REQUEST_VALIDATION_ERROR"}
+		err.Cause.Name = "METHOD_NOT_FOUND"
+	}
+	res := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	if err != nil {
+		res["error"] = err
+	} else {
+		res["result"] = result
+	}
+	return res
+}
+
+type blockParams struct {
+	Finality string      `json:"finality"`
+	BlockID  interface{} `json:"block_id"`
+}
+
+// findBlock returns the block referenced by the params.
+func (s *Server) findBlock(p blockParams) (*block, *rpcError) {
+	switch id := p.BlockID.(type) {
+	case nil:
+		if p.Finality == "" {
+			return nil, requestError("PARSE_ERROR", "missing finality or block_id")
+		}
+		return s.head(), nil
+	case float64:
+		for _, b := range s.blocks {
+			if b.height == int(id) {
+				return b, nil
+			}
+		}
+		return nil, handlerError("UNKNOWN_BLOCK", fmt.Sprintf("DB Not Found Error: BLOCK HEIGHT: %d", int(id)))
+	case string:
+		for _, b := range s.blocks {
+			if b.hash == id {
+				return b, nil
+			}
+		}
+		return nil, handlerError("UNKNOWN_BLOCK", fmt.Sprintf("DB Not Found Error: BLOCK: %s", id))
+	default:
+		return nil, requestError("PARSE_ERROR", fmt.Sprintf("invalid block_id %v", id))
+	}
+}
+
+func blockJSON(b *block) map[string]interface{} {
+	prevHeight := b.height - 1
+	return map[string]interface{}{
+		"author": "neartest",
+		"header": map[string]interface{}{
+			"height":            b.height,
+			"prev_height":       prevHeight,
+			"hash":              b.hash,
+			"prev_hash":         b.prevHash,
+			"epoch_id":          "11111111111111111111111111111111",
+			"timestamp":         b.timestamp,
+			"timestamp_nanosec": fmt.Sprint(b.timestamp),
+			"gas_price":         "100000000",
+			"total_supply":      "0",
+			"chunks_included":   0,
+			"approvals":         []interface{}{},
+		},
+		"chunks": []interface{}{},
+	}
+}
+
+func (s *Server) block(params json.RawMessage) (interface{}, *rpcError) {
+	var p blockParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, requestError("PARSE_ERROR", err.Error())
+	}
+	b, err := s.findBlock(p)
+	if err != nil {
+		return nil, err
+	}
+	return blockJSON(b), nil
+}
+
+func (s *Server) status() interface{} {
+	head := s.head()
+	genesis := s.blocks[0]
+	return map[string]interface{}{
+		"chain_id":                s.chainID,
+		"protocol_version":        1,
+		"latest_protocol_version": 1,
+		"rpc_addr":                s.URL,
+		"version":                 map[string]interface{}{"version": "neartest", "build": "neartest"},
+		"validators":              []interface{}{},
+		"sync_info": map[string]interface{}{
+			"latest_block_hash":     head.hash,
+			"latest_block_height":   head.height,
+			"latest_state_root":     "11111111111111111111111111111111",
+			"latest_block_time":     time.Unix(0, int64(head.timestamp)).UTC().Format(time.RFC3339Nano),
+			"syncing":               false,
+			"earliest_block_hash":   genesis.hash,
+			"earliest_block_height": genesis.height,
+			"earliest_block_time":   time.Unix(0, int64(genesis.timestamp)).UTC().Format(time.RFC3339Nano),
+		},
+	}
+}
+
+func (s *Server) query(params json.RawMessage) (interface{}, *rpcError) {
+	var p struct {
+		blockParams
+		RequestType  string `json:"request_type"`
+		AccountID    string `json:"account_id"`
+		PublicKey    string `json:"public_key"`
+		MethodName   string `json:"method_name"`
+		ArgsBase64   string `json:"args_base64"`
+		PrefixBase64 string `json:"prefix_base64"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, requestError("PARSE_ERROR", err.Error())
+	}
+	b, rerr := s.findBlock(p.blockParams)
+	if rerr != nil {
+		return nil, rerr
+	}
+	a, ok := s.accounts[p.AccountID]
+	if !ok {
+		return nil, handlerError("UNKNOWN_ACCOUNT", fmt.Sprintf(
+			"account %s does not exist while viewing", p.AccountID,
+		))
+	}
+	res := map[string]interface{}{"block_height": b.height, "block_hash": b.hash}
+	switch p.RequestType {
+	case "view_account":
+		res["amount"] = a.amount.String()
+		res["locked"] = a.locked.String()
+		res["code_hash"] = a.codeHash()
+		res["storage_usage"] = a.storageUsage()
+		res["storage_paid_at"] = 0
+	case "view_access_key":
+		key, ok := a.accessKeys[p.PublicKey]
+		if !ok {
+			return nil, handlerError("UNKNOWN_ACCESS_KEY", fmt.Sprintf(
+				"access key %s does not exist while viewing", p.PublicKey,
+			))
+		}
+		res["nonce"] = key.nonce
+		res["permission"] = permissionJSON(key.permission)
+	case "view_state":
+		prefix, err := base64.StdEncoding.DecodeString(p.PrefixBase64)
+		if err != nil {
+			return nil, requestError("PARSE_ERROR", err.Error())
+		}
+		values := []interface{}{}
+		for _, k := range sortedStateKeys(a.state) {
+			if !strings.HasPrefix(k, string(prefix)) {
+				continue
+			}
+			values = append(values, map[string]interface{}{
+				"key":   base64.StdEncoding.EncodeToString([]byte(k)),
+				"value": base64.StdEncoding.EncodeToString(a.state[k]),
+				"proof": []interface{}{},
+			})
+		}
+		res["values"] = values
+		res["proof"] = []interface{}{}
+	case "view_code":
+		if len(a.code) == 0 {
+			return nil, handlerError("NO_CONTRACT_CODE", fmt.Sprintf(
+				"Contract code for contract ID #%s has never been observed on the node", p.AccountID,
+			))
+		}
+		res["code_base64"] = base64.StdEncoding.EncodeToString(a.code)
+		res["hash"] = a.codeHash()
+	case "call_function":
+		args, err := base64.StdEncoding.DecodeString(p.ArgsBase64)
+		if err != nil {
+			return nil, requestError("PARSE_ERROR", err.Error())
+		}
+		fn, ok := s.views[p.AccountID+"/"+p.MethodName]
+		if !ok {
+			return nil, handlerError("CONTRACT_EXECUTION_ERROR", "wasm execution failed with error: MethodNotFound")
+		}
+		view := &View{ContractID: p.AccountID, MethodName: p.MethodName, Args: args, state: a.state}
+		value, err := fn(view)
+		if err != nil {
+			return nil, handlerError("CONTRACT_EXECUTION_ERROR", fmt.Sprintf(
+				"wasm execution failed with error: Smart contract panicked: %v", err,
+			))
+		}
+		result := make([]int, len(value))
+		for i, v := range value {
+			result[i] = int(v)
+		}
+		logs := view.logs
+		if logs == nil {
+			logs = []string{}
+		}
+		res["result"] = result
+		res["logs"] = logs
+	default:
+		return nil, requestError("PARSE_ERROR", fmt.Sprintf("unknown request type %s", p.RequestType))
+	}
+	return res, nil
+}
+
+func (s *Server) changes(params json.RawMessage) (interface{}, *rpcError) {
+	var p struct {
+		blockParams
+		ChangesType     string   `json:"changes_type"`
+		AccountIDs      []string `json:"account_ids"`
+		KeyPrefixBase64 string   `json:"key_prefix_base64"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, requestError("PARSE_ERROR", err.Error())
+	}
+	if p.ChangesType != "data_changes" {
+		return nil, requestError("PARSE_ERROR", fmt.Sprintf("unsupported changes type %s", p.ChangesType))
+	}
+	prefix, err := base64.StdEncoding.DecodeString(p.KeyPrefixBase64)
+	if err != nil {
+		return nil, requestError("PARSE_ERROR", err.Error())
+	}
+	b, rerr := s.findBlock(p.blockParams)
+	if rerr != nil {
+		return nil, rerr
+	}
+	accounts := make(map[string]bool)
+	for _, id := range p.AccountIDs {
+		accounts[id] = true
+	}
+	changes := []interface{}{}
+	for _, c := range b.changes {
+		if !accounts[c.accountID] || !strings.HasPrefix(string(c.key), string(prefix)) {
+			continue
+		}
+		change := map[string]interface{}{
+			"account_id": c.accountID,
+			"key_base64": base64.StdEncoding.EncodeToString(c.key),
+		}
+		changeType := "data_deletion"
+		if c.value != nil {
+			changeType = "data_update"
+			change["value_base64"] = base64.StdEncoding.EncodeToString(c.value)
+		}
+		changes = append(changes, map[string]interface{}{
+			"cause":  map[string]interface{}{"type": "receipt_processing", "receipt_hash": c.receiptHash},
+			"type":   changeType,
+			"change": change,
+		})
+	}
+	return map[string]interface{}{"block_hash": b.hash, "changes": changes}, nil
+}
+
+func (s *Server) broadcastTxCommit(params json.RawMessage) (interface{}, *rpcError) {
+	var p []string
+	if err := json.Unmarshal(params, &p); err != nil || len(p) != 1 {
+		return nil, requestError("PARSE_ERROR", "expected a single base64 encoded transaction")
+	}
+	bytes, err := base64.StdEncoding.DecodeString(p[0])
+	if err != nil {
+		return nil, requestError("PARSE_ERROR", err.Error())
+	}
+	var signed transaction.SignedTransaction
+	if err := borsh.Deserialize(&signed, bytes); err != nil {
+		return nil, requestError("PARSE_ERROR", fmt.Sprintf("decoding transaction: %v", err))
+	}
+	tx := signed.Transaction
+	message, err := borsh.Serialize(tx)
+	if err != nil {
+		return nil, requestError("PARSE_ERROR", err.Error())
+	}
+	hash := sha256.Sum256(message)
+	txHash := base58.Encode(hash[:])
+
+	if txErr := s.validate(signed, hash[:]); txErr != nil {
+		e := handlerError("INVALID_TRANSACTION", txErr.data)
+		return nil, e
+	}
+	signer := s.accounts[tx.SignerID]
+	signer.accessKeys[publicKeyString(tx.PublicKey)].nonce = tx.Nonce
+
+	receiptHash := sha256.Sum256(append(hash[:], 1))
+	receiptID := base58.Encode(receiptHash[:])
+	res, changes := s.execute(tx, receiptID)
+	b := s.produceBlock(changes)
+
+	var status interface{}
+	if res.err != nil {
+		status = map[string]interface{}{"Failure": map[string]interface{}{
+			"ActionError": map[string]interface{}{"index": res.err.index, "kind": res.err.kind},
+		}}
+	} else {
+		status = map[string]interface{}{"SuccessValue": base64.StdEncoding.EncodeToString(res.value)}
+	}
+	logs := res.logs
+	if logs == nil {
+		logs = []string{}
+	}
+	actions := make([]interface{}, len(tx.Actions))
+	for i, a := range tx.Actions {
+		actions[i] = actionJSON(a)
+	}
+	return map[string]interface{}{
+		"status": status,
+		"transaction": map[string]interface{}{
+			"signer_id":   tx.SignerID,
+			"public_key":  publicKeyString(tx.PublicKey),
+			"nonce":       tx.Nonce,
+			"receiver_id": tx.ReceiverID,
+			"actions":     actions,
+			"signature":   "ed25519:" + base58.Encode(signed.Signature.Data[:]),
+			"hash":        txHash,
+		},
+		"transaction_outcome": map[string]interface{}{
+			"id":         txHash,
+			"block_hash": b.hash,
+			"outcome": map[string]interface{}{
+				"logs":         []string{},
+				"receipt_ids":  []string{receiptID},
+				"gas_burnt":    0,
+				"tokens_burnt": "0",
+				"executor_id":  tx.SignerID,
+				"status":       map[string]interface{}{"SuccessReceiptId": receiptID},
+			},
+		},
+		"receipts_outcome": []interface{}{
+			map[string]interface{}{
+				"id":         receiptID,
+				"block_hash": b.hash,
+				"outcome": map[string]interface{}{
+					"logs":         logs,
+					"receipt_ids":  []string{},
+					"gas_burnt":    0,
+					"tokens_burnt": "0",
+					"executor_id":  tx.ReceiverID,
+					"status":       status,
+				},
+			},
+		},
+	}, nil
+}
+
+// validate returns an error if the transaction can't be executed.
+func (s *Server) validate(signed transaction.SignedTransaction, hash []byte) *txError {
+	tx := signed.Transaction
+	known := false
+	for _, b := range s.blocks {
+		known = known || b.hash == base58.Encode(tx.BlockHash[:])
+	}
+	if !known {
+		return invalidTx("Expired", nil)
+	}
+	signer, ok := s.accounts[tx.SignerID]
+	if !ok {
+		return invalidTx("SignerDoesNotExist", map[string]interface{}{"signer_id": tx.SignerID})
+	}
+	pk := publicKeyString(tx.PublicKey)
+	key, ok := signer.accessKeys[pk]
+	if !ok {
+		return invalidTx("InvalidAccessKeyError", map[string]interface{}{
+			"AccessKeyNotFound": map[string]interface{}{"account_id": tx.SignerID, "public_key": pk},
+		})
+	}
+	if !ed25519.Verify(tx.PublicKey.Data[:], hash, signed.Signature.Data[:]) {
+		return invalidTx("InvalidSignature", nil)
+	}
+	if tx.Nonce <= key.nonce {
+		return invalidTx("InvalidNonce", map[string]interface{}{"tx_nonce": tx.Nonce, "ak_nonce": key.nonce})
+	}
+	if err := checkPermission(key, tx); err != nil {
+		return err
+	}
+	cost := totalCost(tx.Actions)
+	if signer.amount.Cmp(cost) < 0 {
+		return invalidTx("NotEnoughBalance", map[string]interface{}{
+			"signer_id": tx.SignerID,
+			"balance":   signer.amount.String(),
+			"cost":      cost.String(),
+		})
+	}
+	return nil
+}
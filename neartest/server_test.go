@@ -0,0 +1,105 @@
+package neartest_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/textileio/near-api-go/account"
+	"github.com/textileio/near-api-go/keys"
+	"github.com/textileio/near-api-go/neartest"
+	"github.com/textileio/near-api-go/transaction"
+	"github.com/textileio/near-api-go/util"
+)
+
+var ctx = context.Background()
+
+func TestFunctionCallFailureReverts(t *testing.T) {
+	s := neartest.NewServer()
+	defer s.Close()
+	signer, err := keys.NewKeyPairFromRandom("ed25519")
+	require.NoError(t, err)
+	s.AddAccount("alice.test", big.NewInt(1000))
+	s.AddAccessKey("alice.test", signer.GetPublicKey())
+	s.AddAccount("token.test", big.NewInt(0))
+	s.RegisterCall("token.test", "mint", func(c *neartest.Call) ([]byte, error) {
+		c.Set([]byte("supply"), []byte("10"))
+		c.Log("minted")
+		if c.Deposit.Sign() == 0 {
+			return nil, errors.New("deposit required")
+		}
+		return []byte("10"), nil
+	})
+
+	config, err := s.Config(signer)
+	require.NoError(t, err)
+	defer config.RPCClient.Close()
+	a := account.NewAccount(config, "alice.test")
+
+	_, err = a.FunctionCall(ctx, "token.test", "mint")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Smart contract panicked: deposit required")
+	state, err := account.NewAccount(config, "token.test").ViewState(ctx, account.ViewStateWithFinality("final"))
+	require.NoError(t, err)
+	require.Empty(t, state.Values)
+
+	res, err := a.FunctionCall(ctx, "token.test", "mint", transaction.FunctionCallWithDeposit(*big.NewInt(100)))
+	require.NoError(t, err)
+	require.Equal(t, []string{"minted"}, res.ReceiptsOutcome[0].Outcome.Logs)
+	status, ok := res.GetStatus()
+	require.True(t, ok)
+	require.Nil(t, status.Failure)
+	require.Equal(t, "MTA=", status.SuccessValue)
+	require.Equal(t, big.NewInt(900), s.Balance("alice.test"))
+	require.Equal(t, big.NewInt(100), s.Balance("token.test"))
+	nonce, ok := s.Nonce("alice.test", signer.GetPublicKey())
+	require.True(t, ok)
+	require.Equal(t, uint64(2), nonce)
+}
+
+func TestInvalidTransaction(t *testing.T) {
+	s := neartest.NewServer()
+	defer s.Close()
+	signer, err := keys.NewKeyPairFromRandom("ed25519")
+	require.NoError(t, err)
+	s.AddAccount("alice.test", big.NewInt(10))
+	s.AddAccessKey("alice.test", signer.GetPublicKey())
+	s.AddAccount("bob.test", big.NewInt(0))
+
+	config, err := s.Config(signer)
+	require.NoError(t, err)
+	defer config.RPCClient.Close()
+	a := account.NewAccount(config, "alice.test")
+
+	_, err = a.SignAndSendTransaction(ctx, "bob.test", transaction.TransferAction(*big.NewInt(100)))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "NotEnoughBalance")
+	require.Equal(t, big.NewInt(10), s.Balance("alice.test"))
+
+	other, err := keys.NewKeyPairFromRandom("ed25519")
+	require.NoError(t, err)
+	config.Signer = other
+	_, err = a.SignAndSendTransaction(ctx, "bob.test", transaction.TransferAction(*big.NewInt(1)))
+	require.Error(t, err)
+}
+
+func TestUnknownBlock(t *testing.T) {
+	s := neartest.NewServer()
+	defer s.Close()
+	s.AddAccount("alice.test", big.NewInt(10))
+	height := s.ProduceBlock()
+	require.Equal(t, 2, height)
+
+	config, err := s.Config(nil)
+	require.NoError(t, err)
+	defer config.RPCClient.Close()
+	a := account.NewAccount(config, "alice.test")
+
+	_, err = a.State(ctx, account.StateWithBlockHeight(2))
+	require.NoError(t, err)
+	_, err = a.State(ctx, account.StateWithBlockHeight(3))
+	require.Error(t, err)
+	require.True(t, util.IsUnknownBlockError(err))
+}
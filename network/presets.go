@@ -0,0 +1,68 @@
+// Package network provides presets of the public NEAR networks, and loads client
+// settings from files and env vars.
+package network
+
+import "sort"
+
+const (
+	// Mainnet is the name of the mainnet preset.
+	Mainnet = "mainnet"
+	// Testnet is the name of the testnet preset.
+	Testnet = "testnet"
+	// Betanet is the name of the betanet preset.
+	Betanet = "betanet"
+	// Localnet is the name of the preset for a node running locally.
+	Localnet = "localnet"
+)
+
+var presets = map[string]Settings{
+	Mainnet: {
+		Network:         Mainnet,
+		NetworkID:       "mainnet",
+		NodeURL:         "https://rpc.mainnet.near.org",
+		ArchivalNodeURL: "https://archival-rpc.mainnet.near.org",
+		HelperURL:       "https://helper.mainnet.near.org",
+		WalletURL:       "https://wallet.near.org",
+		ExplorerURL:     "https://explorer.near.org",
+	},
+	Testnet: {
+		Network:         Testnet,
+		NetworkID:       "testnet",
+		NodeURL:         "https://rpc.testnet.near.org",
+		ArchivalNodeURL: "https://archival-rpc.testnet.near.org",
+		HelperURL:       "https://helper.testnet.near.org",
+		WalletURL:       "https://wallet.testnet.near.org",
+		ExplorerURL:     "https://explorer.testnet.near.org",
+	},
+	Betanet: {
+		Network:     Betanet,
+		NetworkID:   "betanet",
+		NodeURL:     "https://rpc.betanet.near.org",
+		HelperURL:   "https://helper.betanet.near.org",
+		WalletURL:   "https://wallet.betanet.near.org",
+		ExplorerURL: "https://explorer.betanet.near.org",
+	},
+	Localnet: {
+		Network:       Localnet,
+		NetworkID:     "localnet",
+		NodeURL:       "http://localhost:3030",
+		WalletURL:     "http://localhost:4000/wallet",
+		MasterAccount: "test.near",
+	},
+}
+
+// Preset returns the settings of a named network, and whether the network is known.
+func Preset(name string) (Settings, bool) {
+	s, ok := presets[name]
+	return s, ok
+}
+
+// Presets returns the names of the known networks.
+func Presets() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
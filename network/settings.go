@@ -0,0 +1,194 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/textileio/near-api-go/jsonrpc"
+	"github.com/textileio/near-api-go/keys"
+	"github.com/textileio/near-api-go/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Env vars read by LoadEnv and Load.
+const (
+	// EnvConfig is the path of a settings file read by Load.
+	EnvConfig          = "NEAR_CONFIG"
+	EnvNetwork         = "NEAR_NETWORK"
+	EnvNetworkID       = "NEAR_NETWORK_ID"
+	EnvNodeURL         = "NEAR_NODE_URL"
+	EnvArchivalNodeURL = "NEAR_ARCHIVAL_NODE_URL"
+	EnvHelperURL       = "NEAR_HELPER_URL"
+	EnvWalletURL       = "NEAR_WALLET_URL"
+	EnvExplorerURL     = "NEAR_EXPLORER_URL"
+	EnvMasterAccount   = "NEAR_MASTER_ACCOUNT"
+	EnvInitialBalance  = "NEAR_INITIAL_BALANCE"
+	EnvPrivateKey      = "NEAR_PRIVATE_KEY"
+)
+
+// Settings configure a client for a network.
+type Settings struct {
+	// Network is the name of a preset, like "testnet". Empty settings are filled from the preset by Resolve.
+	Network string `json:"network,omitempty" yaml:"network,omitempty"`
+	// NetworkID is the id of the network.
+	NetworkID string `json:"network_id,omitempty" yaml:"network_id,omitempty"`
+	// NodeURL is the url of the RPC node.
+	NodeURL string `json:"node_url,omitempty" yaml:"node_url,omitempty"`
+	// ArchivalNodeURL is the url of an archival RPC node, optional.
+	ArchivalNodeURL string `json:"archival_node_url,omitempty" yaml:"archival_node_url,omitempty"`
+	// HelperURL is the url of the NEAR contract helper.
+	HelperURL string `json:"helper_url,omitempty" yaml:"helper_url,omitempty"`
+	// WalletURL is the url of the NEAR wallet.
+	WalletURL string `json:"wallet_url,omitempty" yaml:"wallet_url,omitempty"`
+	// ExplorerURL is the url of the NEAR explorer.
+	ExplorerURL string `json:"explorer_url,omitempty" yaml:"explorer_url,omitempty"`
+	// MasterAccount is the account used to create accounts.
+	MasterAccount string `json:"master_account,omitempty" yaml:"master_account,omitempty"`
+	// InitialBalance is the balance in yoctoNEAR transferred to created accounts.
+	InitialBalance string `json:"initial_balance,omitempty" yaml:"initial_balance,omitempty"`
+	// PrivateKey is the curve-prefixed base58 private key of the signer, like "ed25519:...".
+	PrivateKey string `json:"private_key,omitempty" yaml:"private_key,omitempty"`
+}
+
+// Merge returns the settings with the non-empty settings of other overriding them. If other
+// selects a different Network, the network id and urls of the settings are dropped first, so
+// they're filled from the preset of the new network by Resolve instead of mixing networks.
+func (s Settings) Merge(other Settings) Settings {
+	if other.Network != "" && other.Network != s.Network {
+		s.NetworkID = ""
+		s.NodeURL = ""
+		s.ArchivalNodeURL = ""
+		s.HelperURL = ""
+		s.WalletURL = ""
+		s.ExplorerURL = ""
+	}
+	set := func(dst *string, src string) {
+		if src != "" {
+			*dst = src
+		}
+	}
+	set(&s.Network, other.Network)
+	set(&s.NetworkID, other.NetworkID)
+	set(&s.NodeURL, other.NodeURL)
+	set(&s.ArchivalNodeURL, other.ArchivalNodeURL)
+	set(&s.HelperURL, other.HelperURL)
+	set(&s.WalletURL, other.WalletURL)
+	set(&s.ExplorerURL, other.ExplorerURL)
+	set(&s.MasterAccount, other.MasterAccount)
+	set(&s.InitialBalance, other.InitialBalance)
+	set(&s.PrivateKey, other.PrivateKey)
+	return s
+}
+
+// Resolve fills the empty settings from the preset of the network, and validates them.
+func (s Settings) Resolve() (Settings, error) {
+	if s.Network != "" {
+		preset, ok := Preset(s.Network)
+		if !ok {
+			return Settings{}, fmt.Errorf("unknown network %s, must be one of %s", s.Network, strings.Join(Presets(), ", "))
+		}
+		s = preset.Merge(s)
+	}
+	if s.NodeURL == "" {
+		return Settings{}, fmt.Errorf("no network or node url configured")
+	}
+	if s.NetworkID == "" {
+		s.NetworkID = s.Network
+	}
+	if s.InitialBalance != "" {
+		if _, ok := new(big.Int).SetString(s.InitialBalance, 10); !ok {
+			return Settings{}, fmt.Errorf("invalid initial balance %s", s.InitialBalance)
+		}
+	}
+	return s, nil
+}
+
+// Config resolves the settings and creates a Config with RPC clients for the node and archival node
+// using the provided options.
+func (s Settings) Config(opts ...jsonrpc.Option) (*types.Config, error) {
+	s, err := s.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	config := &types.Config{
+		NetworkID:     s.NetworkID,
+		HelperURL:     s.HelperURL,
+		WalletURL:     s.WalletURL,
+		MasterAccount: s.MasterAccount,
+	}
+	if s.InitialBalance != "" {
+		config.InitialBalance, _ = new(big.Int).SetString(s.InitialBalance, 10)
+	}
+	if s.PrivateKey != "" {
+		signer, err := keys.NewKeyPairFromString(s.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("parsing private key: %v", err)
+		}
+		config.Signer = signer
+	}
+	config.RPCClient, err = jsonrpc.DialHTTP(s.NodeURL, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating rpc client: %v", err)
+	}
+	if s.ArchivalNodeURL != "" {
+		config.ArchivalRPCClient, err = jsonrpc.DialHTTP(s.ArchivalNodeURL, opts...)
+		if err != nil {
+			config.RPCClient.Close()
+			return nil, fmt.Errorf("creating archival rpc client: %v", err)
+		}
+	}
+	return config, nil
+}
+
+// LoadFile reads settings from a YAML or JSON file. Files with a .json extension are
+// decoded as JSON, and other files as YAML.
+func LoadFile(path string) (Settings, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Settings{}, fmt.Errorf("reading settings file: %v", err)
+	}
+	var s Settings
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &s)
+	} else {
+		err = yaml.Unmarshal(data, &s)
+	}
+	if err != nil {
+		return Settings{}, fmt.Errorf("decoding settings file: %v", err)
+	}
+	return s, nil
+}
+
+// LoadEnv reads settings from the NEAR_* env vars.
+func LoadEnv() Settings {
+	return Settings{
+		Network:         os.Getenv(EnvNetwork),
+		NetworkID:       os.Getenv(EnvNetworkID),
+		NodeURL:         os.Getenv(EnvNodeURL),
+		ArchivalNodeURL: os.Getenv(EnvArchivalNodeURL),
+		HelperURL:       os.Getenv(EnvHelperURL),
+		WalletURL:       os.Getenv(EnvWalletURL),
+		ExplorerURL:     os.Getenv(EnvExplorerURL),
+		MasterAccount:   os.Getenv(EnvMasterAccount),
+		InitialBalance:  os.Getenv(EnvInitialBalance),
+		PrivateKey:      os.Getenv(EnvPrivateKey),
+	}
+}
+
+// Load reads settings from the file at NEAR_CONFIG, if set, overridden by the other NEAR_* env vars,
+// and resolves them.
+func Load() (Settings, error) {
+	var s Settings
+	if path := os.Getenv(EnvConfig); path != "" {
+		var err error
+		if s, err = LoadFile(path); err != nil {
+			return Settings{}, err
+		}
+	}
+	return s.Merge(LoadEnv()).Resolve()
+}
@@ -0,0 +1,112 @@
+package network
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	s, err := Settings{Network: Testnet, NodeURL: "https://rpc.example.com"}.Resolve()
+	require.NoError(t, err)
+	require.Equal(t, "testnet", s.NetworkID)
+	require.Equal(t, "https://rpc.example.com", s.NodeURL)
+	require.Equal(t, "https://archival-rpc.testnet.near.org", s.ArchivalNodeURL)
+	require.Equal(t, "https://wallet.testnet.near.org", s.WalletURL)
+
+	s, err = Settings{NodeURL: "http://localhost:3030", NetworkID: "custom"}.Resolve()
+	require.NoError(t, err)
+	require.Equal(t, "custom", s.NetworkID)
+
+	_, err = Settings{Network: "devnet"}.Resolve()
+	require.Error(t, err)
+	_, err = Settings{}.Resolve()
+	require.Error(t, err)
+	_, err = Settings{Network: Mainnet, InitialBalance: "1.5"}.Resolve()
+	require.Error(t, err)
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "near.yaml")
+	require.NoError(t, ioutil.WriteFile(yamlPath, []byte(`
+network: mainnet
+master_account: master.near
+initial_balance: "1000000000000000000000000"
+`), 0644))
+	jsonPath := filepath.Join(dir, "near.json")
+	require.NoError(t, ioutil.WriteFile(jsonPath, []byte(`{"network": "localnet", "master_account": "root.near"}`), 0644))
+
+	s, err := LoadFile(jsonPath)
+	require.NoError(t, err)
+	require.Equal(t, Settings{Network: Localnet, MasterAccount: "root.near"}, s)
+
+	setenv(t, EnvConfig, yamlPath)
+	setenv(t, EnvNodeURL, "https://rpc.example.com/?apikey=key")
+	s, err = Load()
+	require.NoError(t, err)
+	require.Equal(t, "mainnet", s.NetworkID)
+	require.Equal(t, "https://rpc.example.com/?apikey=key", s.NodeURL)
+	require.Equal(t, "https://helper.mainnet.near.org", s.HelperURL)
+	require.Equal(t, "master.near", s.MasterAccount)
+
+	setenv(t, EnvNetwork, Testnet)
+	s, err = Load()
+	require.NoError(t, err)
+	require.Equal(t, "testnet", s.NetworkID)
+	require.Equal(t, "https://rpc.example.com/?apikey=key", s.NodeURL)
+	require.Equal(t, "https://wallet.testnet.near.org", s.WalletURL)
+
+	// Urls of the file's network aren't used for the network selected by the env.
+	require.NoError(t, ioutil.WriteFile(yamlPath, []byte(`
+network: mainnet
+network_id: mainnet
+archival_node_url: https://archival.example.com
+master_account: master.near
+initial_balance: "1000000000000000000000000"
+`), 0644))
+	s, err = Load()
+	require.NoError(t, err)
+	require.Equal(t, "testnet", s.NetworkID)
+	require.Equal(t, "https://archival-rpc.testnet.near.org", s.ArchivalNodeURL)
+
+	config, err := s.Config()
+	require.NoError(t, err)
+	defer config.RPCClient.Close()
+	require.NotNil(t, config.ArchivalRPCClient)
+	require.Equal(t, "testnet", config.NetworkID)
+	require.Equal(t, "master.near", config.MasterAccount)
+	balance, _ := new(big.Int).SetString("1000000000000000000000000", 10)
+	require.Equal(t, balance, config.InitialBalance)
+	require.Nil(t, config.Signer)
+}
+
+func TestConfigPrivateKey(t *testing.T) {
+	_, err := Settings{Network: Localnet, PrivateKey: "ed25519:invalid"}.Config()
+	require.Error(t, err)
+
+	config, err := Settings{
+		Network:    Localnet,
+		PrivateKey: "ed25519:3D4YudUahN1nawWogh8pAKSj92sUNMdbZGjn7kERKzYoTy8tnFQuwoGUC51DowKqorvkr2pytJSnwuSbsNVfqygr",
+	}.Config()
+	require.NoError(t, err)
+	defer config.RPCClient.Close()
+	require.NotNil(t, config.Signer)
+	require.Nil(t, config.ArchivalRPCClient)
+}
+
+func setenv(t *testing.T, key, value string) {
+	prev, ok := os.LookupEnv(key)
+	require.NoError(t, os.Setenv(key, value))
+	t.Cleanup(func() {
+		if ok {
+			_ = os.Setenv(key, prev)
+		} else {
+			_ = os.Unsetenv(key)
+		}
+	})
+}
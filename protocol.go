@@ -0,0 +1,274 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/textileio/near-api-go/internal/call"
+	itypes "github.com/textileio/near-api-go/internal/types"
+	"github.com/textileio/near-api-go/jsonrpc"
+	"github.com/textileio/near-api-go/types"
+	"github.com/textileio/near-api-go/util"
+)
+
+// GasPriceResponse holds information about the gas price.
+type GasPriceResponse struct {
+	GasPrice string `json:"gas_price"`
+}
+
+// Rational is a fraction encoded as [numerator, denominator].
+type Rational [2]int64
+
+// Fee is the cost of an operation in gas.
+// SendSir is used when the sender and receiver are the same account.
+type Fee struct {
+	SendSir    uint64 `json:"send_sir"`
+	SendNotSir uint64 `json:"send_not_sir"`
+	Execution  uint64 `json:"execution"`
+}
+
+// SendFee returns the gas burnt when sending, sir reporting whether sender is receiver.
+func (f Fee) SendFee(sir bool) uint64 {
+	if sir {
+		return f.SendSir
+	}
+	return f.SendNotSir
+}
+
+// ExecFee returns the gas burnt when executing.
+func (f Fee) ExecFee() uint64 {
+	return f.Execution
+}
+
+// DataReceiptCreationConfig holds the fees for creating data receipts.
+type DataReceiptCreationConfig struct {
+	BaseCost    Fee `json:"base_cost"`
+	CostPerByte Fee `json:"cost_per_byte"`
+}
+
+// AccessKeyCreationConfig holds the fees for adding access keys.
+type AccessKeyCreationConfig struct {
+	FullAccessCost          Fee `json:"full_access_cost"`
+	FunctionCallCost        Fee `json:"function_call_cost"`
+	FunctionCallCostPerByte Fee `json:"function_call_cost_per_byte"`
+}
+
+// ActionCreationConfig holds the fees for creating actions.
+type ActionCreationConfig struct {
+	CreateAccountCost         Fee                     `json:"create_account_cost"`
+	DeployContractCost        Fee                     `json:"deploy_contract_cost"`
+	DeployContractCostPerByte Fee                     `json:"deploy_contract_cost_per_byte"`
+	FunctionCallCost          Fee                     `json:"function_call_cost"`
+	FunctionCallCostPerByte   Fee                     `json:"function_call_cost_per_byte"`
+	TransferCost              Fee                     `json:"transfer_cost"`
+	StakeCost                 Fee                     `json:"stake_cost"`
+	AddKeyCost                AccessKeyCreationConfig `json:"add_key_cost"`
+	DeleteKeyCost             Fee                     `json:"delete_key_cost"`
+	DeleteAccountCost         Fee                     `json:"delete_account_cost"`
+}
+
+// StorageUsageConfig describes how storage usage is accounted.
+type StorageUsageConfig struct {
+	NumBytesAccount     uint64 `json:"num_bytes_account"`
+	NumExtraBytesRecord uint64 `json:"num_extra_bytes_record"`
+}
+
+// RuntimeFeesConfig holds the fees of the runtime.
+type RuntimeFeesConfig struct {
+	ActionReceiptCreationConfig       Fee                       `json:"action_receipt_creation_config"`
+	DataReceiptCreationConfig         DataReceiptCreationConfig `json:"data_receipt_creation_config"`
+	ActionCreationConfig              ActionCreationConfig      `json:"action_creation_config"`
+	StorageUsageConfig                StorageUsageConfig        `json:"storage_usage_config"`
+	BurntGasReward                    Rational                  `json:"burnt_gas_reward"`
+	PessimisticGasPriceInflationRatio Rational                  `json:"pessimistic_gas_price_inflation_ratio"`
+}
+
+// AccountCreationConfig holds the rules for creating accounts.
+type AccountCreationConfig struct {
+	MinAllowedTopLevelAccountLength int    `json:"min_allowed_top_level_account_length"`
+	RegistrarAccountID              string `json:"registrar_account_id"`
+}
+
+// RuntimeConfig holds the configuration of the runtime.
+type RuntimeConfig struct {
+	StorageAmountPerByte  string                `json:"storage_amount_per_byte"`
+	TransactionCosts      RuntimeFeesConfig     `json:"transaction_costs"`
+	WasmConfig            json.RawMessage       `json:"wasm_config"`
+	AccountCreationConfig AccountCreationConfig `json:"account_creation_config"`
+}
+
+// StorageCost returns the amount of yoctoNEAR that must be locked to store numBytes bytes.
+func (rc *RuntimeConfig) StorageCost(numBytes uint64) (*big.Int, error) {
+	perByte, ok := (&big.Int{}).SetString(rc.StorageAmountPerByte, 10)
+	if !ok {
+		return nil, fmt.Errorf("parsing storage amount per byte %q", rc.StorageAmountPerByte)
+	}
+	return perByte.Mul(perByte, (&big.Int{}).SetUint64(numBytes)), nil
+}
+
+// AccountInfo holds information about a genesis validator account.
+type AccountInfo struct {
+	AccountID string `json:"account_id"`
+	PublicKey string `json:"public_key"`
+	Amount    string `json:"amount"`
+}
+
+// GenesisConfigResponse holds the genesis configuration of the network.
+type GenesisConfigResponse struct {
+	ProtocolVersion                 int            `json:"protocol_version"`
+	GenesisTime                     time.Time      `json:"genesis_time"`
+	ChainID                         string         `json:"chain_id"`
+	GenesisHeight                   int            `json:"genesis_height"`
+	NumBlockProducerSeats           int            `json:"num_block_producer_seats"`
+	NumBlockProducerSeatsPerShard   []int          `json:"num_block_producer_seats_per_shard"`
+	AvgHiddenValidatorSeatsPerShard []int          `json:"avg_hidden_validator_seats_per_shard"`
+	DynamicResharding               bool           `json:"dynamic_resharding"`
+	ProtocolUpgradeStakeThreshold   Rational       `json:"protocol_upgrade_stake_threshold"`
+	EpochLength                     int            `json:"epoch_length"`
+	GasLimit                        uint64         `json:"gas_limit"`
+	MinGasPrice                     string         `json:"min_gas_price"`
+	MaxGasPrice                     string         `json:"max_gas_price"`
+	BlockProducerKickoutThreshold   int            `json:"block_producer_kickout_threshold"`
+	ChunkProducerKickoutThreshold   int            `json:"chunk_producer_kickout_threshold"`
+	OnlineMinThreshold              Rational       `json:"online_min_threshold"`
+	OnlineMaxThreshold              Rational       `json:"online_max_threshold"`
+	GasPriceAdjustmentRate          Rational       `json:"gas_price_adjustment_rate"`
+	RuntimeConfig                   *RuntimeConfig `json:"runtime_config,omitempty"`
+	Validators                      []AccountInfo  `json:"validators"`
+	TransactionValidityPeriod       int            `json:"transaction_validity_period"`
+	ProtocolRewardRate              Rational       `json:"protocol_reward_rate"`
+	MaxInflationRate                Rational       `json:"max_inflation_rate"`
+	TotalSupply                     string         `json:"total_supply"`
+	NumBlocksPerYear                int            `json:"num_blocks_per_year"`
+	ProtocolTreasuryAccount         string         `json:"protocol_treasury_account"`
+	FishermenThreshold              string         `json:"fishermen_threshold"`
+	MinimumStakeDivisor             int            `json:"minimum_stake_divisor"`
+}
+
+// ProtocolConfigResponse holds the protocol configuration in effect at a block.
+type ProtocolConfigResponse struct {
+	ProtocolVersion                 int           `json:"protocol_version"`
+	GenesisTime                     time.Time     `json:"genesis_time"`
+	ChainID                         string        `json:"chain_id"`
+	GenesisHeight                   int           `json:"genesis_height"`
+	NumBlockProducerSeats           int           `json:"num_block_producer_seats"`
+	NumBlockProducerSeatsPerShard   []int         `json:"num_block_producer_seats_per_shard"`
+	AvgHiddenValidatorSeatsPerShard []int         `json:"avg_hidden_validator_seats_per_shard"`
+	DynamicResharding               bool          `json:"dynamic_resharding"`
+	ProtocolUpgradeStakeThreshold   Rational      `json:"protocol_upgrade_stake_threshold"`
+	EpochLength                     int           `json:"epoch_length"`
+	GasLimit                        uint64        `json:"gas_limit"`
+	MinGasPrice                     string        `json:"min_gas_price"`
+	MaxGasPrice                     string        `json:"max_gas_price"`
+	BlockProducerKickoutThreshold   int           `json:"block_producer_kickout_threshold"`
+	ChunkProducerKickoutThreshold   int           `json:"chunk_producer_kickout_threshold"`
+	OnlineMinThreshold              Rational      `json:"online_min_threshold"`
+	OnlineMaxThreshold              Rational      `json:"online_max_threshold"`
+	GasPriceAdjustmentRate          Rational      `json:"gas_price_adjustment_rate"`
+	RuntimeConfig                   RuntimeConfig `json:"runtime_config"`
+	TransactionValidityPeriod       int           `json:"transaction_validity_period"`
+	ProtocolRewardRate              Rational      `json:"protocol_reward_rate"`
+	MaxInflationRate                Rational      `json:"max_inflation_rate"`
+	NumBlocksPerYear                int           `json:"num_blocks_per_year"`
+	ProtocolTreasuryAccount         string        `json:"protocol_treasury_account"`
+	FishermenThreshold              string        `json:"fishermen_threshold"`
+	MinimumStakeDivisor             int           `json:"minimum_stake_divisor"`
+}
+
+// GasPriceOption controls the behavior when calling GasPrice.
+type GasPriceOption func(*itypes.GasPriceRequest)
+
+// GasPriceWithBlockReference specifies the block to query the gas price for.
+// The block must be referenced by height or hash.
+func GasPriceWithBlockReference(ref types.BlockReference) GasPriceOption {
+	return func(gr *itypes.GasPriceRequest) {
+		gr.Finality = string(ref.Finality())
+		gr.BlockID = ref.BlockID()
+	}
+}
+
+// GasPriceWithBlockHeight specifies the block height to query the gas price for.
+func GasPriceWithBlockHeight(blockHeight int) GasPriceOption {
+	return GasPriceWithBlockReference(types.BlockHeight(blockHeight))
+}
+
+// GasPriceWithBlockHash specifies the block hash to query the gas price for.
+func GasPriceWithBlockHash(blockHash string) GasPriceOption {
+	return GasPriceWithBlockReference(types.BlockHash(blockHash))
+}
+
+// GasPrice queries the gas price. The latest block is used if no option is provided.
+func (c *Client) GasPrice(ctx context.Context, opts ...GasPriceOption) (*GasPriceResponse, error) {
+	req := &itypes.GasPriceRequest{}
+	for _, opt := range opts {
+		opt(req)
+	}
+	if req.Finality != "" {
+		return nil, fmt.Errorf("gas price can't be queried by finality, provide a block height or block hash")
+	}
+	var res GasPriceResponse
+	if err := call.Block(ctx, c.config, &res, "gas_price", req.BlockID); err != nil {
+		return nil, fmt.Errorf("calling gas price rpc: %v", util.MapRPCError(err))
+	}
+	return &res, nil
+}
+
+// GenesisConfig queries the genesis configuration of the network.
+func (c *Client) GenesisConfig(ctx context.Context) (*GenesisConfigResponse, error) {
+	var res GenesisConfigResponse
+	if err := call.RPC(ctx, c.config, &res, "EXPERIMENTAL_genesis_config"); err != nil {
+		return nil, fmt.Errorf("calling genesis config rpc: %v", util.MapRPCError(err))
+	}
+	return &res, nil
+}
+
+// ProtocolConfigOption controls the behavior when calling ProtocolConfig.
+type ProtocolConfigOption func(*itypes.BlockRequest)
+
+// ProtocolConfigWithBlockReference specifies the block to query the protocol config for.
+func ProtocolConfigWithBlockReference(ref types.BlockReference) ProtocolConfigOption {
+	return func(br *itypes.BlockRequest) {
+		br.Finality = string(ref.Finality())
+		br.BlockID = ref.BlockID()
+	}
+}
+
+// ProtocolConfigWithFinality specifies the finality to be used when querying the protocol config.
+func ProtocolConfigWithFinality(finalaity string) ProtocolConfigOption {
+	return ProtocolConfigWithBlockReference(types.BlockFinality(types.Finality(finalaity)))
+}
+
+// ProtocolConfigWithBlockHeight specifies the block height to query the protocol config for.
+func ProtocolConfigWithBlockHeight(blockHeight int) ProtocolConfigOption {
+	return ProtocolConfigWithBlockReference(types.BlockHeight(blockHeight))
+}
+
+// ProtocolConfigWithBlockHash specifies the block hash to query the protocol config for.
+func ProtocolConfigWithBlockHash(blockHash string) ProtocolConfigOption {
+	return ProtocolConfigWithBlockReference(types.BlockHash(blockHash))
+}
+
+// ProtocolConfig queries the protocol configuration in effect at a block.
+func (c *Client) ProtocolConfig(ctx context.Context, opts ...ProtocolConfigOption) (*ProtocolConfigResponse, error) {
+	req := &itypes.BlockRequest{}
+	for _, opt := range opts {
+		opt(req)
+	}
+	if err := itypes.CheckBlockReference(req.Finality, req.BlockID); err != nil {
+		return nil, err
+	}
+	var res ProtocolConfigResponse
+	if err := call.Block(
+		ctx,
+		c.config,
+		&res,
+		"EXPERIMENTAL_protocol_config",
+		jsonrpc.NewNamedParams(req),
+	); err != nil {
+		return nil, fmt.Errorf("calling protocol config rpc: %v", util.MapRPCError(err))
+	}
+	return &res, nil
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/textileio/near-api-go/internal/call"
+	itypes "github.com/textileio/near-api-go/internal/types"
+	"github.com/textileio/near-api-go/jsonrpc"
+	"github.com/textileio/near-api-go/transaction"
+	"github.com/textileio/near-api-go/util"
+)
+
+// DataReceiver holds information about a receiver of the output data of an action receipt.
+type DataReceiver struct {
+	DataID     string `json:"data_id"`
+	ReceiverID string `json:"receiver_id"`
+}
+
+// ActionReceipt holds information about a receipt that executes actions.
+type ActionReceipt struct {
+	SignerID            string                   `json:"signer_id"`
+	SignerPublicKey     string                   `json:"signer_public_key"`
+	GasPrice            string                   `json:"gas_price"`
+	OutputDataReceivers []DataReceiver           `json:"output_data_receivers"`
+	InputDataIDs        []string                 `json:"input_data_ids"`
+	Actions             []transaction.ActionView `json:"actions"`
+}
+
+// DataReceipt holds information about a receipt that carries the result of a promise.
+type DataReceipt struct {
+	DataID string `json:"data_id"`
+	// Data is nil if the promise failed.
+	Data []byte `json:"data"`
+}
+
+// ReceiptBody holds either an ActionReceipt or a DataReceipt.
+type ReceiptBody struct {
+	Action *ActionReceipt `json:"Action,omitempty"`
+	Data   *DataReceipt   `json:"Data,omitempty"`
+}
+
+// Receipt holds information about a receipt.
+type Receipt struct {
+	PredecessorID string      `json:"predecessor_id"`
+	ReceiverID    string      `json:"receiver_id"`
+	ReceiptID     string      `json:"receipt_id"`
+	Body          ReceiptBody `json:"receipt"`
+}
+
+// Receipt queries a receipt by id.
+func (c *Client) Receipt(ctx context.Context, receiptID string) (*Receipt, error) {
+	req := &itypes.ReceiptRequest{ReceiptID: receiptID}
+	var res Receipt
+	if err := call.RPC(ctx, c.config, &res, "EXPERIMENTAL_receipt", jsonrpc.NewNamedParams(req)); err != nil {
+		return nil, fmt.Errorf("calling receipt rpc: %v", util.MapRPCError(err))
+	}
+	return &res, nil
+}
@@ -0,0 +1,47 @@
+package recorder
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// KeyFunc returns the key used to match a request against the recorded interactions.
+type KeyFunc func(method string, params json.RawMessage) string
+
+type config struct {
+	httpClient *http.Client
+	strict     bool
+	keyFunc    KeyFunc
+}
+
+var defaultConfig = config{
+	httpClient: http.DefaultClient,
+	keyFunc:    DefaultKey,
+}
+
+// Option controls the behavior of a Recorder.
+type Option func(*config)
+
+// WithHTTPClient specifies the http.Client used to reach the upstream endpoint.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *config) {
+		c.httpClient = client
+	}
+}
+
+// WithStrict makes a replaying Recorder fail calls that weren't recorded, or that are
+// made more times than recorded, instead of sending them to the upstream endpoint
+// and repeating the last recorded response.
+func WithStrict() Option {
+	return func(c *config) {
+		c.strict = true
+	}
+}
+
+// WithKeyFunc specifies how requests are matched against the recorded interactions.
+// Defaults to DefaultKey.
+func WithKeyFunc(keyFunc KeyFunc) Option {
+	return func(c *config) {
+		c.keyFunc = keyFunc
+	}
+}
@@ -0,0 +1,374 @@
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	logging "github.com/textileio/go-log/v2"
+	"github.com/textileio/near-api-go/jsonrpc"
+)
+
+var (
+	log = logging.Logger("nearclient/recorder")
+)
+
+// replayURL is dialed by clients of a replaying Recorder without an upstream endpoint.
+const replayURL = "http://recorder.invalid"
+
+// Mode is the mode of a Recorder.
+type Mode int
+
+const (
+	// ModeReplay answers requests with the responses recorded in the golden file.
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to the upstream endpoint and records the responses in the golden file.
+	ModeRecord
+)
+
+// String implements fmt.Stringer.
+func (m Mode) String() string {
+	switch m {
+	case ModeReplay:
+		return "replay"
+	case ModeRecord:
+		return "record"
+	default:
+		return fmt.Sprintf("Mode(%d)", int(m))
+	}
+}
+
+// ModeFromEnv returns ModeRecord if the environment variable is set to a true value, like "1" or "true",
+// and ModeReplay otherwise.
+func ModeFromEnv(name string) Mode {
+	if record, _ := strconv.ParseBool(os.Getenv(name)); record {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// Interaction is a recorded JSON-RPC call.
+type Interaction struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  json.RawMessage `json:"error,omitempty"`
+}
+
+type golden struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+type request struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   json.RawMessage `json:"error,omitempty"`
+}
+
+// Recorder records JSON-RPC calls to a golden file and replays them later.
+//
+// In ModeRecord, requests are sent to the upstream endpoint and every call, including
+// each call of a batch, is recorded. The golden file is written by Close.
+// In ModeReplay, calls are matched against the recorded ones by method and params, and
+// answered with the recorded result or error. A call made several times is answered with
+// the recorded responses in order, and the last one is repeated once they're exhausted.
+// Calls that weren't recorded are sent to the upstream endpoint, unless WithStrict is used.
+//
+// Recorder implements http.RoundTripper, use Client to create a jsonrpc.Client
+// for types.Config.
+type Recorder struct {
+	cfg  config
+	path string
+	mode Mode
+	url  string
+
+	lk           sync.Mutex
+	interactions []Interaction
+	recorded     map[string][]Interaction
+	replayed     map[string]int
+}
+
+// New creates a Recorder using the golden file at path. The url is the upstream endpoint,
+// it's required in ModeRecord and optional in ModeReplay.
+func New(path string, mode Mode, url string, opts ...Option) (*Recorder, error) {
+	cfg := defaultConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	r := &Recorder{
+		cfg:      cfg,
+		path:     path,
+		mode:     mode,
+		url:      url,
+		recorded: make(map[string][]Interaction),
+		replayed: make(map[string]int),
+	}
+	switch mode {
+	case ModeRecord:
+		if url == "" {
+			return nil, fmt.Errorf("an upstream url is required to record")
+		}
+	case ModeReplay:
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading golden file: %v", err)
+		}
+		var g golden
+		if err := json.Unmarshal(data, &g); err != nil {
+			return nil, fmt.Errorf("unmarshaling golden file: %v", err)
+		}
+		for _, i := range g.Interactions {
+			key := cfg.keyFunc(i.Method, i.Params)
+			r.recorded[key] = append(r.recorded[key], i)
+		}
+		r.interactions = g.Interactions
+	default:
+		return nil, fmt.Errorf("unknown mode %v", mode)
+	}
+	return r, nil
+}
+
+// Client creates a jsonrpc.Client that sends its requests through the Recorder.
+func (r *Recorder) Client() (*jsonrpc.HTTPClient, error) {
+	url := r.url
+	if url == "" {
+		url = replayURL
+	}
+	client, err := jsonrpc.DialHTTP(url, jsonrpc.WithHTTPClient(&http.Client{
+		Transport: r,
+		Timeout:   r.cfg.httpClient.Timeout,
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("creating rpc client: %v", err)
+	}
+	return client, nil
+}
+
+// Interactions returns the recorded calls.
+func (r *Recorder) Interactions() []Interaction {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	res := make([]Interaction, len(r.interactions))
+	copy(res, r.interactions)
+	return res
+}
+
+// Close writes the golden file in ModeRecord. It's a no-op in ModeReplay.
+func (r *Recorder) Close() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.lk.Lock()
+	g := golden{Interactions: r.interactions}
+	r.lk.Unlock()
+	if g.Interactions == nil {
+		g.Interactions = []Interaction{}
+	}
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling golden file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("creating golden file dir: %v", err)
+	}
+	if err := ioutil.WriteFile(r.path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing golden file: %v", err)
+	}
+	return nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		b, err := ioutil.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading request body: %v", err)
+		}
+		body = b
+	}
+	reqs, batch, err := parseRequests(body)
+	if err != nil {
+		return nil, err
+	}
+	if r.mode == ModeRecord {
+		return r.record(req, body, reqs)
+	}
+
+	resps, err := r.replay(reqs)
+	if err != nil {
+		if r.cfg.strict || r.url == "" {
+			return nil, err
+		}
+		log.Debugf("sending request upstream: %v", err)
+		return r.send(req, body)
+	}
+	var out interface{} = resps
+	if !batch {
+		out = resps[0]
+	}
+	respBody, err := json.Marshal(out)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling response: %v", err)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+// replay returns the recorded responses for the requests, or an error if any of them can't be answered.
+func (r *Recorder) replay(reqs []request) ([]response, error) {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	keys := make([]string, len(reqs))
+	pending := make(map[string]int)
+	for i, req := range reqs {
+		keys[i] = r.cfg.keyFunc(req.Method, req.Params)
+		recorded := r.recorded[keys[i]]
+		if len(recorded) == 0 {
+			return nil, fmt.Errorf("unexpected call to %s with params %s", req.Method, string(req.Params))
+		}
+		pending[keys[i]]++
+		if r.cfg.strict && r.replayed[keys[i]]+pending[keys[i]] > len(recorded) {
+			return nil, fmt.Errorf(
+				"unexpected call to %s with params %s, it was recorded %d times",
+				req.Method,
+				string(req.Params),
+				len(recorded),
+			)
+		}
+	}
+	resps := make([]response, len(reqs))
+	for i, req := range reqs {
+		recorded := r.recorded[keys[i]]
+		n := r.replayed[keys[i]]
+		if n >= len(recorded) {
+			n = len(recorded) - 1
+		}
+		r.replayed[keys[i]]++
+		resps[i] = response{JSONRPC: "2.0", ID: req.ID, Result: recorded[n].Result, Error: recorded[n].Error}
+	}
+	return resps, nil
+}
+
+// record sends the requests upstream and records the responses.
+func (r *Recorder) record(req *http.Request, body []byte, reqs []request) (*http.Response, error) {
+	resp, err := r.send(req, body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %v", err)
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	var resps []response
+	if isBatch(respBody) {
+		err = json.Unmarshal(respBody, &resps)
+	} else {
+		resps = make([]response, 1)
+		err = json.Unmarshal(respBody, &resps[0])
+	}
+	if err != nil {
+		log.Warnf("not recording unparsable response: %v", err)
+		return resp, nil
+	}
+	byID := make(map[string]response, len(resps))
+	for _, res := range resps {
+		byID[string(res.ID)] = res
+	}
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	for _, req := range reqs {
+		res, ok := byID[string(req.ID)]
+		if !ok {
+			continue
+		}
+		r.interactions = append(r.interactions, Interaction{
+			Method: req.Method,
+			Params: canonical(req.Params),
+			Result: res.Result,
+			Error:  res.Error,
+		})
+	}
+	return resp, nil
+}
+
+func (r *Recorder) send(req *http.Request, body []byte) (*http.Response, error) {
+	out, err := http.NewRequestWithContext(req.Context(), req.Method, req.URL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %v", err)
+	}
+	for k, v := range req.Header {
+		out.Header[k] = v
+	}
+	return r.cfg.httpClient.Do(out)
+}
+
+// DefaultKey matches requests by method and params, ignoring the formatting and key order of the params.
+func DefaultKey(method string, params json.RawMessage) string {
+	return method + " " + string(canonical(params))
+}
+
+// canonical re-encodes JSON so equivalent values have the same encoding.
+func canonical(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return raw
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return raw
+	}
+	return b
+}
+
+func parseRequests(body []byte) ([]request, bool, error) {
+	if isBatch(body) {
+		var reqs []request
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			return nil, false, fmt.Errorf("unmarshaling batch request: %v", err)
+		}
+		return reqs, true, nil
+	}
+	var req request
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, false, fmt.Errorf("unmarshaling request: %v", err)
+	}
+	return []request{req}, false, nil
+}
+
+func isBatch(body []byte) bool {
+	return strings.HasPrefix(strings.TrimSpace(string(body)), "[")
+}
@@ -0,0 +1,120 @@
+package recorder
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	api "github.com/textileio/near-api-go"
+	"github.com/textileio/near-api-go/keys"
+	"github.com/textileio/near-api-go/neartest"
+	"github.com/textileio/near-api-go/types"
+)
+
+var ctx = context.Background()
+
+func TestRecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "golden.json")
+
+	server := neartest.NewServer()
+	server.AddAccount("alice.test", big.NewInt(1000))
+	server.SetCode("alice.test", []byte("code"))
+	pubKey, err := keys.NewPublicKeyFromString("ed25519:H9k5eiU4xXS3M4z8HzKJSLaZdqGdGwBG49o7orNC4eZW")
+	require.NoError(t, err)
+	server.AddAccessKey("alice.test", *pubKey)
+
+	r, err := New(path, ModeRecord, server.URL)
+	require.NoError(t, err)
+	recorded := queryAll(t, r)
+	require.NoError(t, r.Close())
+	server.Close()
+	require.Len(t, r.Interactions(), 4)
+
+	r, err = New(path, ModeReplay, "", WithStrict())
+	require.NoError(t, err)
+	replayed := queryAll(t, r)
+	require.Equal(t, recorded, replayed)
+
+	// The calls were made once each, so making them again is unexpected in strict mode.
+	client, err := r.Client()
+	require.NoError(t, err)
+	defer client.Close()
+	c, err := api.NewClient(&types.Config{RPCClient: client})
+	require.NoError(t, err)
+	_, err = c.ViewCode(ctx, "alice.test", api.ViewCodeWithFinality("final"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unexpected call to query")
+	_, err = c.ViewCode(ctx, "bob.test", api.ViewCodeWithFinality("final"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unexpected call to query")
+}
+
+func TestReplayRepeatsLastResponse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "golden.json")
+	server := neartest.NewServer()
+	defer server.Close()
+	server.AddAccount("alice.test", big.NewInt(1000))
+
+	r, err := New(path, ModeRecord, server.URL)
+	require.NoError(t, err)
+	recorded := queryAll(t, r)
+	require.NoError(t, r.Close())
+
+	r, err = New(path, ModeReplay, "")
+	require.NoError(t, err)
+	require.Equal(t, recorded, queryAll(t, r))
+	require.Equal(t, recorded, queryAll(t, r))
+}
+
+func TestNewReplayMissingFile(t *testing.T) {
+	_, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay, "")
+	require.Error(t, err)
+	_, err = New(filepath.Join(t.TempDir(), "golden.json"), ModeRecord, "")
+	require.Error(t, err)
+}
+
+func TestDefaultKey(t *testing.T) {
+	require.Equal(
+		t,
+		DefaultKey("query", []byte(`{"b": 1, "a": [1, 2]}`)),
+		DefaultKey("query", []byte(`{"a":[1,2],"b":1}`)),
+	)
+	require.NotEqual(t, DefaultKey("query", []byte(`{"a":1}`)), DefaultKey("block", []byte(`{"a":1}`)))
+}
+
+type results struct {
+	Code      *api.ViewCodeResponse
+	CodeErr   string
+	Block     *api.BlockResponse
+	Account   interface{}
+	AccessKey interface{}
+}
+
+// queryAll makes a single call, a failing call and a batch of two calls through the Recorder.
+func queryAll(t *testing.T, r *Recorder) results {
+	client, err := r.Client()
+	require.NoError(t, err)
+	defer client.Close()
+	c, err := api.NewClient(&types.Config{RPCClient: client})
+	require.NoError(t, err)
+
+	var res results
+	res.Block, err = c.Block(ctx, api.BlockWithFinality("final"))
+	require.NoError(t, err)
+	res.Code, err = c.ViewCode(ctx, "alice.test", api.ViewCodeWithFinality("final"))
+	if err != nil {
+		res.CodeErr = err.Error()
+	}
+
+	pubKey, err := keys.NewPublicKeyFromString("ed25519:H9k5eiU4xXS3M4z8HzKJSLaZdqGdGwBG49o7orNC4eZW")
+	require.NoError(t, err)
+	batch := c.NewBatch(types.BlockHeight(res.Block.Header.Height))
+	account := batch.ViewAccount("alice.test")
+	accessKey := batch.ViewAccessKey("alice.test", pubKey)
+	require.NoError(t, batch.Do(ctx))
+	res.Account = account
+	res.AccessKey = accessKey
+	return res
+}
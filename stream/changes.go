@@ -0,0 +1,95 @@
+package stream
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	api "github.com/textileio/near-api-go"
+)
+
+// DataChange is a decoded change to contract data.
+type DataChange struct {
+	BlockHeight int
+	BlockHash   string
+	// Type is either "data_update" or "data_deletion".
+	Type      string
+	AccountID string
+	Key       []byte
+	// Value is nil for a "data_deletion".
+	Value       []byte
+	CauseType   string
+	ReceiptHash string
+}
+
+// WatchDataChanges follows final blocks and sends every change to the data of the provided
+// accounts with keys matching prefix to the changes channel, in order. Changes are sent
+// with blocking sends, so the stream advances only as fast as the consumer reads. A
+// Checkpoint is saved after all changes of a block have been received, so a restarted
+// watcher using the same CheckpointStore resumes with the next block. The stream always
+// follows final blocks, regardless of WithFinality. WatchDataChanges closes the channel
+// when it returns.
+func WatchDataChanges(
+	ctx context.Context,
+	client *api.Client,
+	accountIDs []string,
+	prefix string,
+	changes chan<- DataChange,
+	opts ...Option,
+) error {
+	defer close(changes)
+	s, err := New(client, append(opts, WithFinality("final"))...)
+	if err != nil {
+		return fmt.Errorf("creating stream: %v", err)
+	}
+	return s.Process(ctx, func(ctx context.Context, event Event) error {
+		if event.Type != EventBlock {
+			return nil
+		}
+		res, err := client.DataChanges(
+			ctx,
+			accountIDs,
+			api.DataChangesWithPrefix(prefix),
+			api.DataChangesWithBlockHash(event.Hash),
+		)
+		if err != nil {
+			return fmt.Errorf("getting data changes for block %s: %v", event.Hash, err)
+		}
+		for _, c := range res.Changes {
+			change, err := decodeDataChange(event, c)
+			if err != nil {
+				return err
+			}
+			select {
+			case changes <- change:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+}
+
+func decodeDataChange(event Event, c api.ChangeData) (DataChange, error) {
+	key, err := base64.StdEncoding.DecodeString(c.Change.KeyBase64)
+	if err != nil {
+		return DataChange{}, fmt.Errorf("decoding key: %v", err)
+	}
+	var value []byte
+	if c.Change.ValueBase64 != "" {
+		value, err = base64.StdEncoding.DecodeString(c.Change.ValueBase64)
+		if err != nil {
+			return DataChange{}, fmt.Errorf("decoding value: %v", err)
+		}
+	}
+	return DataChange{
+		BlockHeight: event.Height,
+		BlockHash:   event.Hash,
+		Type:        c.Type,
+		AccountID:   c.Change.AccountID,
+		Key:         key,
+		Value:       value,
+		CauseType:   c.Cause.Type,
+		ReceiptHash: c.Cause.ReceiptHash,
+	}, nil
+}
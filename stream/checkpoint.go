@@ -0,0 +1,116 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Checkpoint records the last block processed by a Stream.
+type Checkpoint struct {
+	Height int    `json:"height"`
+	Hash   string `json:"hash"`
+}
+
+// CheckpointStore persists the Stream Checkpoint so processing can be resumed after a restart.
+type CheckpointStore interface {
+	// Load returns the last saved Checkpoint, or nil if none has been saved yet.
+	Load(ctx context.Context) (*Checkpoint, error)
+	// Save persists the provided Checkpoint.
+	Save(ctx context.Context, checkpoint Checkpoint) error
+}
+
+// MemoryCheckpointStore is an in-memory CheckpointStore.
+type MemoryCheckpointStore struct {
+	lk         sync.Mutex
+	checkpoint *Checkpoint
+}
+
+var _ CheckpointStore = (*MemoryCheckpointStore)(nil)
+
+// NewMemoryCheckpointStore creates a new MemoryCheckpointStore.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{}
+}
+
+// Load implements Load.
+func (s *MemoryCheckpointStore) Load(_ context.Context) (*Checkpoint, error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	if s.checkpoint == nil {
+		return nil, nil
+	}
+	c := *s.checkpoint
+	return &c, nil
+}
+
+// Save implements Save.
+func (s *MemoryCheckpointStore) Save(_ context.Context, checkpoint Checkpoint) error {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	s.checkpoint = &checkpoint
+	return nil
+}
+
+// FileCheckpointStore is a CheckpointStore that persists the Checkpoint as JSON in a file.
+type FileCheckpointStore struct {
+	lk   sync.Mutex
+	path string
+}
+
+var _ CheckpointStore = (*FileCheckpointStore)(nil)
+
+// NewFileCheckpointStore creates a new FileCheckpointStore writing to the provided path.
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+// Load implements Load.
+func (s *FileCheckpointStore) Load(_ context.Context) (*Checkpoint, error) {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	bytes, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading checkpoint file: %v", err)
+	}
+	var c Checkpoint
+	if err := json.Unmarshal(bytes, &c); err != nil {
+		return nil, fmt.Errorf("unmarshaling checkpoint: %v", err)
+	}
+	return &c, nil
+}
+
+// Save implements Save. The file is replaced atomically so a crash never leaves a partial checkpoint.
+func (s *FileCheckpointStore) Save(_ context.Context, checkpoint Checkpoint) error {
+	s.lk.Lock()
+	defer s.lk.Unlock()
+	bytes, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("marshaling checkpoint: %v", err)
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %v", err)
+	}
+	if _, err := tmp.Write(bytes); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("writing temp file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("closing temp file: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("renaming temp file: %v", err)
+	}
+	return nil
+}
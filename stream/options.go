@@ -0,0 +1,68 @@
+package stream
+
+import "time"
+
+type config struct {
+	startHeight   int
+	finality      string
+	withChunks    bool
+	pollInterval  time.Duration
+	rollbackDepth int
+	store         CheckpointStore
+}
+
+var defaultConfig = config{
+	finality:      "final",
+	pollInterval:  time.Second,
+	rollbackDepth: 64,
+}
+
+// Option controls the behavior of a Stream.
+type Option func(*config)
+
+// WithStartHeight specifies the block height to start streaming from.
+// It is ignored if the CheckpointStore already holds a Checkpoint.
+// If not provided, and no Checkpoint exists, the Stream starts at the current head.
+func WithStartHeight(height int) Option {
+	return func(c *config) {
+		c.startHeight = height
+	}
+}
+
+// WithFinality specifies the finality of the blocks to follow, one of
+// "final", "near-final" or "optimistic". Defaults to "final".
+func WithFinality(finality string) Option {
+	return func(c *config) {
+		c.finality = finality
+	}
+}
+
+// WithChunks causes the Stream to fetch and emit the chunks of each block.
+func WithChunks() Option {
+	return func(c *config) {
+		c.withChunks = true
+	}
+}
+
+// WithPollInterval specifies how long to wait before polling for new blocks once the head is reached.
+func WithPollInterval(interval time.Duration) Option {
+	return func(c *config) {
+		c.pollInterval = interval
+	}
+}
+
+// WithRollbackDepth specifies how many recently emitted blocks are remembered
+// in order to roll back non-final blocks.
+func WithRollbackDepth(depth int) Option {
+	return func(c *config) {
+		c.rollbackDepth = depth
+	}
+}
+
+// WithCheckpointStore specifies the CheckpointStore used to persist progress.
+// Defaults to an in-memory store.
+func WithCheckpointStore(store CheckpointStore) Option {
+	return func(c *config) {
+		c.store = store
+	}
+}
@@ -0,0 +1,245 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	logging "github.com/textileio/go-log/v2"
+	api "github.com/textileio/near-api-go"
+)
+
+var (
+	log = logging.Logger("nearclient/stream")
+)
+
+// EventType is the type of an Event.
+type EventType int
+
+const (
+	// EventBlock means a new block was appended to the chain.
+	EventBlock EventType = iota
+	// EventRollback means a previously emitted block is no longer part of the chain.
+	EventRollback
+)
+
+// Event is emitted by a Stream for every block it follows.
+type Event struct {
+	Type   EventType
+	Height int
+	Hash   string
+	// Block is the block for an EventBlock, nil for an EventRollback.
+	Block *api.BlockResponse
+	// Chunks holds the chunks of the block if the Stream was created WithChunks.
+	Chunks []*api.ChunkResponse
+}
+
+// Handler processes an Event. Returning an error stops the Stream.
+type Handler func(ctx context.Context, event Event) error
+
+// Stream follows the chain from a start height, emitting blocks in order.
+type Stream struct {
+	client *api.Client
+	config config
+	// recent holds the most recently emitted blocks, used to detect and roll back forks.
+	recent []Checkpoint
+}
+
+// New creates a new Stream.
+func New(client *api.Client, opts ...Option) (*Stream, error) {
+	c := defaultConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	switch c.finality {
+	case "final", "near-final", "optimistic":
+	default:
+		return nil, fmt.Errorf("unknown finality %s", c.finality)
+	}
+	if c.pollInterval <= 0 {
+		return nil, fmt.Errorf("poll interval must be positive")
+	}
+	if c.rollbackDepth < 1 {
+		return nil, fmt.Errorf("rollback depth must be at least 1")
+	}
+	if c.store == nil {
+		c.store = NewMemoryCheckpointStore()
+	}
+	return &Stream{
+		client: client,
+		config: c,
+	}, nil
+}
+
+// Run follows the chain and sends each Event to the provided channel.
+// A Checkpoint is saved once the Event has been received, so sends block until
+// the consumer is ready. Run closes the channel when it returns.
+func (s *Stream) Run(ctx context.Context, events chan<- Event) error {
+	defer close(events)
+	return s.Process(ctx, func(ctx context.Context, event Event) error {
+		select {
+		case events <- event:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// Process follows the chain and calls handler for each Event. A Checkpoint is saved
+// each time handler returns successfully. Process returns when the context is
+// canceled or an error occurs.
+func (s *Stream) Process(ctx context.Context, handler Handler) error {
+	next, err := s.startHeight(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		head, err := s.client.Block(ctx, api.BlockWithFinality(s.config.finality))
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			return fmt.Errorf("getting head block: %v", err)
+		}
+		for next <= head.Header.Height {
+			next, err = s.step(ctx, next, handler)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil {
+				return err
+			}
+		}
+		t := time.NewTimer(s.config.pollInterval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// step processes the block at the provided height and returns the next height to process.
+func (s *Stream) step(ctx context.Context, height int, handler Handler) (int, error) {
+	block, err := s.client.Block(ctx, api.BlockWithBlockHeight(height))
+	if isEmptyHeightError(err) {
+		log.Debugf("skipping height %d with no block", height)
+		return height + 1, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("getting block at height %d: %v", height, err)
+	}
+
+	// Heights are skipped when the node reports no block for them, so make sure the block
+	// doesn't follow a block the node failed to return.
+	if last := s.last(); last != nil && block.Header.PrevHeight > last.Height {
+		return 0, fmt.Errorf(
+			"block %s at height %d follows height %d, which the node didn't return after height %d",
+			block.Header.Hash,
+			height,
+			block.Header.PrevHeight,
+			last.Height,
+		)
+	}
+
+	if last := s.last(); last != nil && block.Header.PrevHash != last.Hash {
+		if s.config.finality != "optimistic" {
+			return 0, fmt.Errorf(
+				"block %s at height %d doesn't follow block %s at height %d",
+				block.Header.Hash,
+				height,
+				last.Hash,
+				last.Height,
+			)
+		}
+		if len(s.recent) == 1 {
+			// Without the parent of the rolled back block, the fork couldn't be checked.
+			return 0, fmt.Errorf(
+				"block %s at height %d forks deeper than the %d blocks remembered for rollbacks",
+				block.Header.Hash,
+				height,
+				s.config.rollbackDepth,
+			)
+		}
+		s.recent = s.recent[:len(s.recent)-1]
+		event := Event{Type: EventRollback, Height: last.Height, Hash: last.Hash}
+		if err := handler(ctx, event); err != nil {
+			return 0, err
+		}
+		if err := s.config.store.Save(ctx, *s.last()); err != nil {
+			return 0, fmt.Errorf("saving checkpoint: %v", err)
+		}
+		// Re-process the height of the rolled back block, which may now hold a different block.
+		return last.Height, nil
+	}
+
+	event := Event{
+		Type:   EventBlock,
+		Height: block.Header.Height,
+		Hash:   block.Header.Hash,
+		Block:  block,
+	}
+	if s.config.withChunks {
+		for _, header := range block.Chunks {
+			chunk, err := s.client.Chunk(ctx, api.ChunkWithChunkID(header.ChunkHash))
+			if err != nil {
+				return 0, fmt.Errorf("getting chunk %s: %v", header.ChunkHash, err)
+			}
+			event.Chunks = append(event.Chunks, chunk)
+		}
+	}
+	if err := handler(ctx, event); err != nil {
+		return 0, err
+	}
+	checkpoint := Checkpoint{Height: block.Header.Height, Hash: block.Header.Hash}
+	if err := s.config.store.Save(ctx, checkpoint); err != nil {
+		return 0, fmt.Errorf("saving checkpoint: %v", err)
+	}
+	s.recent = append(s.recent, checkpoint)
+	if len(s.recent) > s.config.rollbackDepth {
+		s.recent = s.recent[1:]
+	}
+	return height + 1, nil
+}
+
+func (s *Stream) startHeight(ctx context.Context) (int, error) {
+	checkpoint, err := s.config.store.Load(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("loading checkpoint: %v", err)
+	}
+	if checkpoint != nil {
+		// Keep the blocks remembered by a previous run if they end at the checkpoint.
+		if last := s.last(); last == nil || *last != *checkpoint {
+			s.recent = nil
+			if checkpoint.Hash != "" {
+				s.recent = []Checkpoint{*checkpoint}
+			}
+		}
+		return checkpoint.Height + 1, nil
+	}
+	if s.config.startHeight > 0 {
+		return s.config.startHeight, nil
+	}
+	head, err := s.client.Block(ctx, api.BlockWithFinality(s.config.finality))
+	if err != nil {
+		return 0, fmt.Errorf("getting head block: %v", err)
+	}
+	return head.Header.Height, nil
+}
+
+func (s *Stream) last() *Checkpoint {
+	if len(s.recent) == 0 {
+		return nil
+	}
+	return &s.recent[len(s.recent)-1]
+}
+
+// isEmptyHeightError reports whether the error means no block was produced at the requested
+// height. Blocks that were garbage collected, or are unknown to the node for other reasons,
+// don't match.
+func isEmptyHeightError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "DB Not Found Error: BLOCK HEIGHT")
+}
@@ -0,0 +1,346 @@
+package stream
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	api "github.com/textileio/near-api-go"
+	"github.com/textileio/near-api-go/jsonrpc"
+	"github.com/textileio/near-api-go/types"
+)
+
+var ctx = context.Background()
+
+func TestSkippedHeights(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 2, 4, 5, 7)
+	s := makeStream(t, chain, WithStartHeight(1))
+
+	events := collect(t, s, 5)
+	requireHeights(t, events, 1, 2, 4, 5, 7)
+	for _, e := range events {
+		require.Equal(t, EventBlock, e.Type)
+		require.NotNil(t, e.Block)
+	}
+}
+
+func TestResumeFromCheckpoint(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 2, 3, 4, 5)
+	store := NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoint.json"))
+
+	s := makeStream(t, chain, WithStartHeight(1), WithCheckpointStore(store))
+	requireHeights(t, collect(t, s, 3), 1, 2, 3)
+
+	cp, err := store.Load(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, cp.Height)
+
+	s = makeStream(t, chain, WithStartHeight(1), WithCheckpointStore(store))
+	requireHeights(t, collect(t, s, 2), 4, 5)
+}
+
+func TestOptimisticRollback(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 2, 3)
+	store := NewMemoryCheckpointStore()
+	s := makeStream(t, chain, WithStartHeight(1), WithFinality("optimistic"), WithCheckpointStore(store))
+	requireHeights(t, collect(t, s, 3), 1, 2, 3)
+
+	// Replace block 3 with a fork block and build on top of it.
+	chain.fork(3)
+	chain.extend(4)
+	events := collect(t, s, 3)
+	require.Equal(t, EventRollback, events[0].Type)
+	require.Equal(t, 3, events[0].Height)
+	require.Equal(t, EventBlock, events[1].Type)
+	require.Equal(t, 3, events[1].Height)
+	require.NotEqual(t, events[0].Hash, events[1].Hash)
+	require.Equal(t, 4, events[2].Height)
+
+	cp, err := store.Load(ctx)
+	require.NoError(t, err)
+	require.Equal(t, events[2].Hash, cp.Hash)
+}
+
+func TestFinalDiscontinuity(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 2)
+	s := makeStream(t, chain, WithStartHeight(1))
+	requireHeights(t, collect(t, s, 2), 1, 2)
+
+	chain.fork(2)
+	chain.extend(3)
+	err := s.Process(ctx, func(context.Context, Event) error { return nil })
+	require.Error(t, err)
+}
+
+func TestUnavailableBlocks(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 2, 3, 4)
+	chain.gc[1] = true
+	s := makeStream(t, chain, WithStartHeight(1))
+	err := s.Process(ctx, func(context.Context, Event) error { return nil })
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "garbage collected")
+
+	chain.hidden[3] = true
+	s = makeStream(t, chain, WithStartHeight(2))
+	var heights []int
+	err = s.Process(ctx, func(_ context.Context, e Event) error {
+		heights = append(heights, e.Height)
+		return nil
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "follows height 3")
+	require.Equal(t, []int{2}, heights)
+}
+
+func TestRollbackDepthExhausted(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 2, 3)
+	s := makeStream(t, chain, WithStartHeight(1), WithFinality("optimistic"), WithRollbackDepth(2))
+	requireHeights(t, collect(t, s, 3), 1, 2, 3)
+
+	// Only blocks 2 and 3 are remembered, so a fork from height 2 can't be checked.
+	chain.fork(2)
+	chain.extend(4)
+	var events []Event
+	err := s.Process(ctx, func(_ context.Context, e Event) error {
+		events = append(events, e)
+		return nil
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "deeper than the 2 blocks")
+	require.Len(t, events, 1)
+	require.Equal(t, EventRollback, events[0].Type)
+	require.Equal(t, 3, events[0].Height)
+}
+
+func TestWithChunks(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1)
+	s := makeStream(t, chain, WithStartHeight(1), WithChunks())
+	events := collect(t, s, 1)
+	require.Len(t, events[0].Chunks, 1)
+	require.Equal(t, events[0].Block.Chunks[0].ChunkHash, events[0].Chunks[0].Header.ChunkHash)
+}
+
+func TestWatchDataChanges(t *testing.T) {
+	chain := newFakeChain()
+	chain.extend(1, 2, 3)
+	chain.changes["block-1-0"] = []api.ChangeData{
+		dataChange("a.testnet", "k1", "v1", "r1"),
+		dataChange("a.testnet", "k2", "v2", "r1"),
+	}
+	chain.changes["block-3-0"] = []api.ChangeData{
+		dataChange("a.testnet", "k1", "v3", "r2"),
+	}
+	store := NewMemoryCheckpointStore()
+	client := makeClient(t, chain)
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+	changes := make(chan DataChange)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- WatchDataChanges(
+			ctx,
+			client,
+			[]string{"a.testnet"},
+			"k",
+			changes,
+			WithStartHeight(1),
+			WithCheckpointStore(store),
+			WithPollInterval(time.Millisecond*10),
+		)
+	}()
+	var got []DataChange
+	for c := range changes {
+		got = append(got, c)
+		if len(got) == 3 {
+			cancel()
+		}
+	}
+	require.ErrorIs(t, <-errc, context.Canceled)
+	require.Len(t, got, 3)
+	require.Equal(t, []byte("k1"), got[0].Key)
+	require.Equal(t, []byte("v1"), got[0].Value)
+	require.Equal(t, 1, got[0].BlockHeight)
+	require.Equal(t, "r1", got[0].ReceiptHash)
+	require.Equal(t, []byte("k2"), got[1].Key)
+	require.Equal(t, []byte("v3"), got[2].Value)
+	require.Equal(t, 3, got[2].BlockHeight)
+	require.Equal(t, "r2", got[2].ReceiptHash)
+}
+
+func dataChange(accountID, key, value, receiptHash string) api.ChangeData {
+	return api.ChangeData{
+		Cause: api.Cause{Type: "receipt_processing", ReceiptHash: receiptHash},
+		Type:  "data_update",
+		Change: api.Change{
+			AccountID:   accountID,
+			KeyBase64:   base64.StdEncoding.EncodeToString([]byte(key)),
+			ValueBase64: base64.StdEncoding.EncodeToString([]byte(value)),
+		},
+	}
+}
+
+func collect(t *testing.T, s *Stream, n int) []Event {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+	var events []Event
+	err := s.Process(ctx, func(_ context.Context, e Event) error {
+		events = append(events, e)
+		if len(events) == n {
+			cancel()
+		}
+		return nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Len(t, events, n)
+	return events
+}
+
+func requireHeights(t *testing.T, events []Event, heights ...int) {
+	require.Len(t, events, len(heights))
+	for i, h := range heights {
+		require.Equal(t, h, events[i].Height)
+	}
+}
+
+func makeStream(t *testing.T, chain *fakeChain, opts ...Option) *Stream {
+	s, err := New(makeClient(t, chain), append([]Option{WithPollInterval(time.Millisecond * 10)}, opts...)...)
+	require.NoError(t, err)
+	return s
+}
+
+func makeClient(t *testing.T, chain *fakeChain) *api.Client {
+	server := httptest.NewServer(chain)
+	t.Cleanup(server.Close)
+	rpcClient, err := jsonrpc.DialHTTP(server.URL)
+	require.NoError(t, err)
+	t.Cleanup(rpcClient.Close)
+	client, err := api.NewClient(&types.Config{RPCClient: rpcClient, NetworkID: "testnet"})
+	require.NoError(t, err)
+	return client
+}
+
+type fakeChain struct {
+	lk      sync.Mutex
+	blocks  map[int]api.BlockResponse
+	changes map[string][]api.ChangeData
+	head    int
+	forks   int
+	// gc holds heights of blocks reported as garbage collected.
+	gc map[int]bool
+	// hidden holds heights of blocks reported as not found, like on a node that isn't synced.
+	hidden map[int]bool
+}
+
+func newFakeChain() *fakeChain {
+	return &fakeChain{
+		blocks:  make(map[int]api.BlockResponse),
+		changes: make(map[string][]api.ChangeData),
+		gc:      make(map[int]bool),
+		hidden:  make(map[int]bool),
+	}
+}
+
+func (c *fakeChain) extend(heights ...int) {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	for _, h := range heights {
+		prev := c.blocks[c.head]
+		hash := fmt.Sprintf("block-%d-%d", h, c.forks)
+		c.blocks[h] = api.BlockResponse{
+			Header: api.BlockHeader{Height: h, Hash: hash, PrevHash: prev.Header.Hash, PrevHeight: prev.Header.Height},
+			Chunks: []api.ChunkHeader{{ChunkHash: "chunk-" + hash}},
+		}
+		c.head = h
+	}
+}
+
+// fork removes blocks from the provided height and rebuilds the removed heights with new hashes.
+func (c *fakeChain) fork(height int) {
+	c.lk.Lock()
+	head := c.head
+	c.forks++
+	for h := height; h <= head; h++ {
+		delete(c.blocks, h)
+	}
+	c.head = 0
+	for h := range c.blocks {
+		if h > c.head {
+			c.head = h
+		}
+	}
+	c.lk.Unlock()
+	for h := height; h <= head; h++ {
+		c.extend(h)
+	}
+}
+
+func (c *fakeChain) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params struct {
+			Finality string      `json:"finality"`
+			BlockID  interface{} `json:"block_id"`
+			ChunkID  string      `json:"chunk_id"`
+		} `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.lk.Lock()
+	defer c.lk.Unlock()
+
+	res := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	switch req.Method {
+	case "block":
+		if req.Params.Finality != "" {
+			res["result"] = c.blocks[c.head]
+			break
+		}
+		height := int(req.Params.BlockID.(float64))
+		if c.gc[height] {
+			res["error"] = map[string]interface{}{
+				"code":    -32000,
+				"message": "Server error",
+				"data":    fmt.Sprintf("The data for block #%d is garbage collected on this node", height),
+				"cause":   map[string]interface{}{"name": "GARBAGE_COLLECTED_BLOCK"},
+			}
+			break
+		}
+		b, ok := c.blocks[height]
+		if !ok || c.hidden[height] {
+			res["error"] = map[string]interface{}{
+				"code":    -32000,
+				"message": "Server error",
+				"data":    fmt.Sprintf("DB Not Found Error: BLOCK HEIGHT: %v \n Cause: Unknown", req.Params.BlockID),
+			}
+			break
+		}
+		res["result"] = b
+	case "EXPERIMENTAL_changes":
+		hash, _ := req.Params.BlockID.(string)
+		res["result"] = api.DataChangesResponse{BlockHash: hash, Changes: c.changes[hash]}
+	case "chunk":
+		res["result"] = api.ChunkResponse{Header: api.ChunkHeader{ChunkHash: req.Params.ChunkID}}
+	default:
+		res["error"] = map[string]interface{}{"code": -32601, "message": "method not found"}
+	}
+	_ = json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,246 @@
+package transaction
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/textileio/near-api-go/keys"
+)
+
+type functionCallJSON struct {
+	MethodName string `json:"method_name"`
+	Args       string `json:"args"`
+	Gas        uint64 `json:"gas"`
+	Deposit    string `json:"deposit"`
+}
+
+type accessKeyJSON struct {
+	Nonce      uint64          `json:"nonce"`
+	Permission json.RawMessage `json:"permission"`
+}
+
+type functionCallPermissionJSON struct {
+	FunctionCall struct {
+		Allowance   *string  `json:"allowance"`
+		ReceiverID  string   `json:"receiver_id"`
+		MethodNames []string `json:"method_names"`
+	} `json:"FunctionCall"`
+}
+
+// errUnsupportedAction is returned by decodeAction for actions this package doesn't support.
+var errUnsupportedAction = errors.New("unsupported action")
+
+// UnmarshalJSON decodes an Action from the JSON representation used in RPC responses,
+// such as {"Transfer":{"deposit":"1"}} or "CreateAccount". Actions this package doesn't
+// support return an error. Use ActionView to decode them.
+func (a *Action) UnmarshalJSON(b []byte) error {
+	name, raw, err := splitAction(b)
+	if err != nil {
+		return err
+	}
+	action, err := decodeAction(name, raw)
+	if err != nil {
+		return fmt.Errorf("decoding %s action: %v", name, err)
+	}
+	*a = action
+	return nil
+}
+
+// UnmarshalJSON decodes an ActionView like Action.UnmarshalJSON does, except that actions
+// this package doesn't support decode to an UnknownAction. Only malformed supported actions
+// return an error.
+func (a *ActionView) UnmarshalJSON(b []byte) error {
+	name, raw, err := splitAction(b)
+	if err != nil {
+		return err
+	}
+	action, err := decodeAction(name, raw)
+	if err == errUnsupportedAction {
+		*a = ActionView{
+			Action:  Action{Enum: UnknownActionEnum},
+			Unknown: &UnknownAction{Name: name, Raw: append(json.RawMessage(nil), raw...)},
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("decoding %s action: %v", name, err)
+	}
+	*a = ActionView{Action: action}
+	return nil
+}
+
+// splitAction returns the name and body of an action encoded as {"<name>": <body>}. Actions
+// without a body, encoded as "<name>", are returned with the encoded name as body.
+func splitAction(b []byte) (string, json.RawMessage, error) {
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		return name, b, nil
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return "", nil, fmt.Errorf("unmarshaling action: %v", err)
+	}
+	if len(m) != 1 {
+		return "", nil, fmt.Errorf("expected a single action, got %d", len(m))
+	}
+	for name, raw := range m {
+		return name, raw, nil
+	}
+	return "", nil, nil
+}
+
+func decodeAction(name string, raw json.RawMessage) (Action, error) {
+	switch name {
+	case "CreateAccount":
+		return CreateAccountAction(), nil
+	case "DeployContract":
+		var v struct {
+			Code string `json:"code"`
+		}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return Action{}, err
+		}
+		code, err := base64.StdEncoding.DecodeString(v.Code)
+		if err != nil {
+			return Action{}, fmt.Errorf("decoding code: %v", err)
+		}
+		return DeployContractAction(code), nil
+	case "FunctionCall":
+		var v functionCallJSON
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return Action{}, err
+		}
+		args, err := base64.StdEncoding.DecodeString(v.Args)
+		if err != nil {
+			return Action{}, fmt.Errorf("decoding args: %v", err)
+		}
+		deposit, err := parseAmount(v.Deposit)
+		if err != nil {
+			return Action{}, err
+		}
+		return Action{
+			Enum: 2,
+			FunctionCall: FunctionCall{
+				MethodName: v.MethodName,
+				Args:       args,
+				Gas:        v.Gas,
+				Deposit:    *deposit,
+			},
+		}, nil
+	case "Transfer":
+		var v struct {
+			Deposit string `json:"deposit"`
+		}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return Action{}, err
+		}
+		deposit, err := parseAmount(v.Deposit)
+		if err != nil {
+			return Action{}, err
+		}
+		return TransferAction(*deposit), nil
+	case "Stake":
+		var v struct {
+			Stake     string `json:"stake"`
+			PublicKey string `json:"public_key"`
+		}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return Action{}, err
+		}
+		stake, err := parseAmount(v.Stake)
+		if err != nil {
+			return Action{}, err
+		}
+		pk, err := keys.NewPublicKeyFromString(v.PublicKey)
+		if err != nil {
+			return Action{}, fmt.Errorf("decoding public key: %v", err)
+		}
+		return StakeAction(*stake, *pk), nil
+	case "AddKey":
+		var v struct {
+			PublicKey string        `json:"public_key"`
+			AccessKey accessKeyJSON `json:"access_key"`
+		}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return Action{}, err
+		}
+		pk, err := keys.NewPublicKeyFromString(v.PublicKey)
+		if err != nil {
+			return Action{}, fmt.Errorf("decoding public key: %v", err)
+		}
+		accessKey, err := decodeAccessKey(v.AccessKey)
+		if err != nil {
+			return Action{}, err
+		}
+		return AddKeyAction(*pk, accessKey), nil
+	case "DeleteKey":
+		var v struct {
+			PublicKey string `json:"public_key"`
+		}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return Action{}, err
+		}
+		pk, err := keys.NewPublicKeyFromString(v.PublicKey)
+		if err != nil {
+			return Action{}, fmt.Errorf("decoding public key: %v", err)
+		}
+		return DeleteKeyAction(*pk), nil
+	case "DeleteAccount":
+		var v struct {
+			BeneficiaryID string `json:"beneficiary_id"`
+		}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return Action{}, err
+		}
+		return DeleteAccountAction(v.BeneficiaryID), nil
+	default:
+		return Action{}, errUnsupportedAction
+	}
+}
+
+func decodeAccessKey(v accessKeyJSON) (AccessKey, error) {
+	var fullAccess string
+	if err := json.Unmarshal(v.Permission, &fullAccess); err == nil {
+		if fullAccess != "FullAccess" {
+			return AccessKey{}, fmt.Errorf("unknown permission %s", fullAccess)
+		}
+		return AccessKey{
+			Nonce:      v.Nonce,
+			Permission: AccessKeyPermission{Enum: 1, FullAccess: FullAccessPermission{}},
+		}, nil
+	}
+	var p functionCallPermissionJSON
+	if err := json.Unmarshal(v.Permission, &p); err != nil {
+		return AccessKey{}, fmt.Errorf("unmarshaling permission: %v", err)
+	}
+	var allowance *big.Int
+	if p.FunctionCall.Allowance != nil {
+		a, err := parseAmount(*p.FunctionCall.Allowance)
+		if err != nil {
+			return AccessKey{}, err
+		}
+		allowance = a
+	}
+	return AccessKey{
+		Nonce: v.Nonce,
+		Permission: AccessKeyPermission{
+			Enum: 0,
+			FunctionCall: FunctionCallPermission{
+				Allowance:   allowance,
+				ReceiverID:  p.FunctionCall.ReceiverID,
+				MethodNames: p.FunctionCall.MethodNames,
+			},
+		},
+	}, nil
+}
+
+func parseAmount(s string) (*big.Int, error) {
+	amount, ok := (&big.Int{}).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	return amount, nil
+}
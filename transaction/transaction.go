@@ -10,6 +10,12 @@ import (
 	"github.com/textileio/near-api-go/keys"
 )
 
+// UnknownActionEnum is the Enum of the Action of an ActionView holding an UnknownAction.
+const UnknownActionEnum borsh.Enum = 255
+
+// actionVariants is the number of Action variants, the ones this package can serialize.
+const actionVariants = 8
+
 var (
 	defaultFunctionCallGas     uint64 = 30000000000000
 	defaultFunctionCallDeposit        = *((&big.Int{}).SetInt64(0))
@@ -122,6 +128,22 @@ type DeleteAccount struct {
 	BeneficiaryID string
 }
 
+// ActionView is an action decoded from the JSON of RPC responses, like the actions of chunk
+// transactions and receipts. Actions this package doesn't support, such as Delegate actions,
+// are kept in Unknown, and their Action has the UnknownActionEnum.
+type ActionView struct {
+	Action
+	// Unknown is only set for actions this package doesn't support.
+	Unknown *UnknownAction
+}
+
+// UnknownAction is an action decoded from JSON that this package doesn't support. It keeps the
+// action name and its raw JSON.
+type UnknownAction struct {
+	Name string
+	Raw  json.RawMessage
+}
+
 // CreateAccountAction is a helper to create a CreateAccount action.
 func CreateAccountAction() Action {
 	return Action{Enum: 0, CreateAccount: CreateAccount{}}
@@ -275,6 +297,12 @@ func SignTransaction(
 	accountID string,
 	networkID string,
 ) ([]byte, *SignedTransaction, error) {
+	for i, a := range transaction.Actions {
+		// borsh-go would serialize variants it has no field for as their index alone.
+		if a.Enum >= actionVariants {
+			return nil, nil, fmt.Errorf("action %d has unsupported variant %d", i, a.Enum)
+		}
+	}
 	message, err := borsh.Serialize(transaction)
 	if err != nil {
 		return nil, nil, fmt.Errorf("serializing transaction: %v", err)
@@ -2,6 +2,8 @@ package transaction
 
 import (
 	"encoding/base64"
+	"encoding/json"
+	"math/big"
 	"testing"
 
 	"github.com/near/borsh-go"
@@ -22,3 +24,84 @@ func TestIt(t *testing.T) {
 	s := base64.StdEncoding.EncodeToString(payload)
 	require.NotEmpty(t, s)
 }
+
+func TestActionUnmarshalJSON(t *testing.T) {
+	var actions []Action
+	err := json.Unmarshal([]byte(`[
+		"CreateAccount",
+		{"DeployContract": {"code": "AGFzbQ=="}},
+		{"FunctionCall": {"method_name": "ft_transfer", "args": "e30=", "gas": 30000000000000, "deposit": "1"}},
+		{"Transfer": {"deposit": "1000000000000000000000000"}},
+		{"Stake": {"stake": "5", "public_key": "ed25519:H9k5eiU4xXS3M4z8HzKJSLaZdqGdGwBG49o7orNC4eZW"}},
+		{"AddKey": {
+			"public_key": "ed25519:H9k5eiU4xXS3M4z8HzKJSLaZdqGdGwBG49o7orNC4eZW",
+			"access_key": {"nonce": 0, "permission": "FullAccess"}
+		}},
+		{"AddKey": {
+			"public_key": "ed25519:H9k5eiU4xXS3M4z8HzKJSLaZdqGdGwBG49o7orNC4eZW",
+			"access_key": {"nonce": 0, "permission": {"FunctionCall": {
+				"allowance": null, "receiver_id": "c.testnet", "method_names": ["m"]
+			}}}
+		}},
+		{"DeleteKey": {"public_key": "ed25519:H9k5eiU4xXS3M4z8HzKJSLaZdqGdGwBG49o7orNC4eZW"}},
+		{"DeleteAccount": {"beneficiary_id": "b.testnet"}}
+	]`), &actions)
+	require.NoError(t, err)
+	require.Len(t, actions, 9)
+	for i, a := range actions[:5] {
+		require.Equal(t, borsh.Enum(i), a.Enum)
+	}
+	require.Equal(t, []byte("\x00asm"), actions[1].DeployContract.Code)
+	require.Equal(t, "ft_transfer", actions[2].FunctionCall.MethodName)
+	require.Equal(t, []byte("{}"), actions[2].FunctionCall.Args)
+	require.Equal(t, uint64(30000000000000), actions[2].FunctionCall.Gas)
+	require.Equal(t, "1000000000000000000000000", actions[3].Transfer.Deposit.String())
+	require.Equal(t, borsh.Enum(1), actions[5].AddKey.AccessKey.Permission.Enum)
+	require.Equal(t, borsh.Enum(0), actions[6].AddKey.AccessKey.Permission.Enum)
+	require.Nil(t, actions[6].AddKey.AccessKey.Permission.FunctionCall.Allowance)
+	require.Equal(t, "c.testnet", actions[6].AddKey.AccessKey.Permission.FunctionCall.ReceiverID)
+	require.Equal(t, borsh.Enum(6), actions[7].Enum)
+	require.Equal(t, "b.testnet", actions[8].DeleteAccount.BeneficiaryID)
+
+	var a Action
+	require.Error(t, json.Unmarshal([]byte(`{"Delegate": {"delegate_action": {}, "signature": "ed25519:1"}}`), &a))
+
+	var v ActionView
+	require.NoError(t, json.Unmarshal([]byte(`{"Delegate": {"delegate_action": {}, "signature": "ed25519:1"}}`), &v))
+	require.Equal(t, UnknownActionEnum, v.Enum)
+	require.Equal(t, "Delegate", v.Unknown.Name)
+	require.JSONEq(t, `{"delegate_action": {}, "signature": "ed25519:1"}`, string(v.Unknown.Raw))
+	require.NoError(t, json.Unmarshal([]byte(`"Unknown"`), &v))
+	require.Equal(t, "Unknown", v.Unknown.Name)
+	require.NoError(t, json.Unmarshal([]byte(`{"Transfer": {"deposit": "1"}}`), &v))
+	require.Nil(t, v.Unknown)
+	require.Equal(t, "1", v.Transfer.Deposit.String())
+	require.Error(t, json.Unmarshal([]byte(`{"Transfer": {"deposit": "abc"}}`), &v))
+	require.Error(t, json.Unmarshal([]byte(`{"Transfer": {"deposit": "abc"}}`), &a))
+}
+
+func TestUnsupportedActionVariant(t *testing.T) {
+	signer, err := keys.NewKeyPairFromRandom("ed25519")
+	require.NoError(t, err)
+
+	trans := Transaction{Actions: []Action{TransferAction(*big.NewInt(1))}}
+	_, signed, err := SignTransaction(trans, signer, "", "")
+	require.NoError(t, err)
+	b, err := borsh.Serialize(signed.Transaction)
+	require.NoError(t, err)
+	var decoded Transaction
+	require.NoError(t, borsh.Deserialize(&decoded, b))
+	require.Equal(t, borsh.Enum(3), decoded.Actions[0].Enum)
+	require.Equal(t, "1", decoded.Actions[0].Transfer.Deposit.String())
+
+	// Variant 8 is NEAR's Delegate action, which Action has no field for.
+	for _, enum := range []borsh.Enum{8, UnknownActionEnum} {
+		trans := Transaction{Actions: []Action{{Enum: enum}}}
+		_, _, err = SignTransaction(trans, signer, "", "")
+		require.Error(t, err)
+
+		// The last action variant is followed by the u128 deposit of the Transfer.
+		b[len(b)-17] = byte(enum)
+		require.Error(t, borsh.Deserialize(&decoded, b))
+	}
+}
@@ -0,0 +1,136 @@
+package types
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/textileio/near-api-go/keys"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// MinAccountIDLen is the minimum length of an account id.
+	MinAccountIDLen = 2
+	// MaxAccountIDLen is the maximum length of an account id.
+	MaxAccountIDLen = 64
+)
+
+// AccountID is the id of a NEAR account, like "alice.near".
+type AccountID string
+
+// ParseAccountID returns the AccountID for s, or an error if it isn't valid.
+func ParseAccountID(s string) (AccountID, error) {
+	id := AccountID(s)
+	if err := id.Validate(); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Validate returns an error if the AccountID breaks NEAR's rules: it must be 2 to 64 characters long,
+// made of lowercase letters, digits and the separators '.', '-' and '_', and must neither start nor end
+// with a separator nor have two separators in a row.
+func (id AccountID) Validate() error {
+	s := string(id)
+	if len(s) < MinAccountIDLen || len(s) > MaxAccountIDLen {
+		return fmt.Errorf(
+			"invalid account id %q: length must be between %d and %d",
+			s,
+			MinAccountIDLen,
+			MaxAccountIDLen,
+		)
+	}
+	prevSeparator := true
+	for i, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+			prevSeparator = false
+		case c == '.' || c == '-' || c == '_':
+			if prevSeparator {
+				return fmt.Errorf("invalid account id %q: unexpected separator %q at %d", s, c, i)
+			}
+			prevSeparator = true
+		default:
+			return fmt.Errorf("invalid account id %q: invalid character %q at %d", s, c, i)
+		}
+	}
+	if prevSeparator {
+		return fmt.Errorf("invalid account id %q: can't end with a separator", s)
+	}
+	return nil
+}
+
+func (id AccountID) String() string {
+	return string(id)
+}
+
+// IsTopLevel reports whether the AccountID has no parent, like "near" or an implicit account id.
+func (id AccountID) IsTopLevel() bool {
+	return !strings.Contains(string(id), ".")
+}
+
+// Parent returns the parent of the AccountID, like "near" for "alice.near", and false for
+// top level accounts.
+func (id AccountID) Parent() (AccountID, bool) {
+	i := strings.IndexByte(string(id), '.')
+	if i < 0 {
+		return "", false
+	}
+	return id[i+1:], true
+}
+
+// IsSubAccountOf reports whether the AccountID is a direct sub-account of parent, like "alice.near"
+// of "near". Only the parent account can create its sub-accounts.
+func (id AccountID) IsSubAccountOf(parent AccountID) bool {
+	p, ok := id.Parent()
+	return ok && p == parent
+}
+
+// IsImplicit reports whether the AccountID is the implicit account of an ed25519 public key.
+func (id AccountID) IsImplicit() bool {
+	return len(id) == 2*ed25519.PublicKeySize && isLowerHex(string(id))
+}
+
+// IsEthImplicit reports whether the AccountID is the implicit account of an Ethereum address,
+// like "0x" followed by 40 hex characters.
+func (id AccountID) IsEthImplicit() bool {
+	return len(id) == 42 && strings.HasPrefix(string(id), "0x") && isLowerHex(string(id[2:]))
+}
+
+// ImplicitAccountID returns the implicit account id of an ed25519 public key, the lowercase
+// hex of the key. Transfers to the id create the account, with the key as full access key.
+func ImplicitAccountID(publicKey keys.PublicKey) (AccountID, error) {
+	if publicKey.Type != keys.ED25519 {
+		return "", fmt.Errorf("implicit account ids require an ed25519 key")
+	}
+	if len(publicKey.Data) != ed25519.PublicKeySize {
+		return "", fmt.Errorf("invalid ed25519 public key size %d", len(publicKey.Data))
+	}
+	return AccountID(hex.EncodeToString(publicKey.Data)), nil
+}
+
+// EthImplicitAccountID returns the ETH-implicit account id of an uncompressed secp256k1 public key,
+// with or without the 0x04 prefix. The id is "0x" followed by the hex of the key's Ethereum address,
+// the last 20 bytes of the keccak-256 hash of the key.
+func EthImplicitAccountID(publicKey []byte) (AccountID, error) {
+	if len(publicKey) == 65 && publicKey[0] == 0x04 {
+		publicKey = publicKey[1:]
+	}
+	if len(publicKey) != 64 {
+		return "", fmt.Errorf("invalid uncompressed secp256k1 public key size %d", len(publicKey))
+	}
+	hash := sha3.NewLegacyKeccak256()
+	_, _ = hash.Write(publicKey)
+	return AccountID("0x" + hex.EncodeToString(hash.Sum(nil)[12:])), nil
+}
+
+func isLowerHex(s string) bool {
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package types
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/textileio/near-api-go/keys"
+)
+
+func TestAccountIDValidate(t *testing.T) {
+	for _, id := range []string{
+		"ok",
+		"near",
+		"alice.near",
+		"bob_1-2.alice.near",
+		"0xabcdef0123456789abcdef0123456789abcdef01",
+		"a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2",
+	} {
+		_, err := ParseAccountID(id)
+		require.NoError(t, err, id)
+	}
+	for _, id := range []string{
+		"",
+		"a",
+		"Alice.near",
+		"alice..near",
+		"alice._near",
+		".near",
+		"near.",
+		"-near",
+		"alice@near",
+		"alice near",
+		"a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b23",
+	} {
+		_, err := ParseAccountID(id)
+		require.Error(t, err, id)
+	}
+}
+
+func TestAccountIDRelationships(t *testing.T) {
+	id := AccountID("bob.alice.near")
+	require.False(t, id.IsTopLevel())
+	parent, ok := id.Parent()
+	require.True(t, ok)
+	require.Equal(t, AccountID("alice.near"), parent)
+	require.True(t, id.IsSubAccountOf("alice.near"))
+	require.False(t, id.IsSubAccountOf("near"))
+	require.False(t, AccountID("bobalice.near").IsSubAccountOf("alice.near"))
+
+	require.True(t, AccountID("near").IsTopLevel())
+	_, ok = AccountID("near").Parent()
+	require.False(t, ok)
+}
+
+func TestImplicitAccountID(t *testing.T) {
+	kp, err := keys.NewKeyPairFromRandom("ed25519")
+	require.NoError(t, err)
+	pk := kp.GetPublicKey()
+	id, err := ImplicitAccountID(pk)
+	require.NoError(t, err)
+	require.Equal(t, AccountID(hex.EncodeToString(pk.Data)), id)
+	require.True(t, id.IsImplicit())
+	require.True(t, id.IsTopLevel())
+	require.NoError(t, id.Validate())
+
+	_, err = ImplicitAccountID(keys.PublicKey{Type: keys.ED25519, Data: []byte{1, 2, 3}})
+	require.Error(t, err)
+	require.False(t, AccountID("alice.near").IsImplicit())
+}
+
+func TestEthImplicitAccountID(t *testing.T) {
+	// The public key of the secp256k1 private key 1, the generator point.
+	pubKey, err := hex.DecodeString("04" +
+		"79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798" +
+		"483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8")
+	require.NoError(t, err)
+	id, err := EthImplicitAccountID(pubKey)
+	require.NoError(t, err)
+	require.Equal(t, AccountID("0x7e5f4552091a69125d5dfcb7b8c2659029395bdf"), id)
+	require.True(t, id.IsEthImplicit())
+	require.NoError(t, id.Validate())
+
+	id, err = EthImplicitAccountID(pubKey[1:])
+	require.NoError(t, err)
+	require.Equal(t, AccountID("0x7e5f4552091a69125d5dfcb7b8c2659029395bdf"), id)
+
+	_, err = EthImplicitAccountID(pubKey[:33])
+	require.Error(t, err)
+	require.False(t, AccountID("0x7E5F4552091A69125D5DFCB7B8C2659029395BDF").IsEthImplicit())
+}
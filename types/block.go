@@ -0,0 +1,148 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Finality is the finality of a block.
+type Finality string
+
+const (
+	// FinalityOptimistic uses the latest block, which may still be reverted.
+	FinalityOptimistic Finality = "optimistic"
+	// FinalityNearFinal uses the latest block that is very unlikely to be reverted.
+	FinalityNearFinal Finality = "near-final"
+	// FinalityFinal uses the latest block that can't be reverted.
+	FinalityFinal Finality = "final"
+)
+
+// Validate returns an error if the Finality isn't a known value.
+func (f Finality) Validate() error {
+	switch f {
+	case FinalityOptimistic, FinalityNearFinal, FinalityFinal:
+		return nil
+	default:
+		return fmt.Errorf("unknown finality %q", string(f))
+	}
+}
+
+// ErrNoBlockReference is returned when a query is missing a block reference.
+var ErrNoBlockReference = errors.New("you must provide a block reference: a finality, block height or block hash")
+
+// BlockReference references a block by finality, height or hash.
+// The zero value references no block.
+type BlockReference struct {
+	finality  Finality
+	height    int
+	hasHeight bool
+	hash      string
+}
+
+// Final references the latest final block.
+func Final() BlockReference {
+	return BlockFinality(FinalityFinal)
+}
+
+// Optimistic references the latest block.
+func Optimistic() BlockReference {
+	return BlockFinality(FinalityOptimistic)
+}
+
+// NearFinal references the latest near-final block.
+func NearFinal() BlockReference {
+	return BlockFinality(FinalityNearFinal)
+}
+
+// BlockFinality references the latest block with the provided finality.
+func BlockFinality(finality Finality) BlockReference {
+	return BlockReference{finality: finality}
+}
+
+// BlockHeight references the block at the provided height.
+func BlockHeight(height int) BlockReference {
+	return BlockReference{height: height, hasHeight: true}
+}
+
+// BlockHash references the block with the provided hash.
+func BlockHash(hash string) BlockReference {
+	return BlockReference{hash: hash}
+}
+
+// Finality returns the referenced finality, or an empty Finality if the block is referenced by id.
+func (r BlockReference) Finality() Finality {
+	return r.finality
+}
+
+// Height returns the referenced height and whether the block is referenced by height.
+func (r BlockReference) Height() (int, bool) {
+	return r.height, r.hasHeight
+}
+
+// Hash returns the referenced hash and whether the block is referenced by hash.
+func (r BlockReference) Hash() (string, bool) {
+	return r.hash, r.hash != ""
+}
+
+// BlockID returns the block id used in RPC requests: the height, the hash,
+// or nil if the block is referenced by finality.
+func (r BlockReference) BlockID() interface{} {
+	if r.hasHeight {
+		return r.height
+	}
+	if r.hash != "" {
+		return r.hash
+	}
+	return nil
+}
+
+// IsZero reports whether the BlockReference references no block.
+func (r BlockReference) IsZero() bool {
+	return r.finality == "" && !r.hasHeight && r.hash == ""
+}
+
+// Validate returns an error if the BlockReference doesn't reference a block.
+func (r BlockReference) Validate() error {
+	if r.IsZero() {
+		return ErrNoBlockReference
+	}
+	if r.finality != "" {
+		return r.finality.Validate()
+	}
+	if r.hasHeight && r.height < 0 {
+		return fmt.Errorf("invalid block height %d", r.height)
+	}
+	return nil
+}
+
+// String implements fmt.Stringer.
+func (r BlockReference) String() string {
+	switch {
+	case r.finality != "":
+		return string(r.finality)
+	case r.hasHeight:
+		return fmt.Sprintf("height %d", r.height)
+	case r.hash != "":
+		return fmt.Sprintf("hash %s", r.hash)
+	default:
+		return "none"
+	}
+}
+
+// NewBlockReference creates a BlockReference from the finality and block id fields used in RPC requests.
+// It returns an error if both are set.
+func NewBlockReference(finality string, blockID interface{}) (BlockReference, error) {
+	if finality != "" && blockID != nil {
+		return BlockReference{}, fmt.Errorf("you must provide only one of a finality, block height or block hash")
+	}
+	switch id := blockID.(type) {
+	case nil:
+		return BlockFinality(Finality(finality)), nil
+	case int:
+		return BlockHeight(id), nil
+	case string:
+		return BlockHash(id), nil
+	default:
+		return BlockReference{}, fmt.Errorf("invalid block id %v", blockID)
+	}
+}
@@ -0,0 +1,56 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockReference(t *testing.T) {
+	require.Equal(t, FinalityFinal, Final().Finality())
+	require.Nil(t, Final().BlockID())
+	require.NoError(t, Optimistic().Validate())
+	require.NoError(t, NearFinal().Validate())
+
+	h := BlockHeight(0)
+	height, ok := h.Height()
+	require.True(t, ok)
+	require.Equal(t, 0, height)
+	require.Equal(t, 0, h.BlockID())
+	require.Empty(t, h.Finality())
+	require.NoError(t, h.Validate())
+
+	ref := BlockHash("abc")
+	hash, ok := ref.Hash()
+	require.True(t, ok)
+	require.Equal(t, "abc", hash)
+	require.Equal(t, "abc", ref.BlockID())
+
+	require.True(t, BlockReference{}.IsZero())
+	require.ErrorIs(t, BlockReference{}.Validate(), ErrNoBlockReference)
+	require.Error(t, BlockFinality("eventually").Validate())
+	require.Error(t, BlockHeight(-1).Validate())
+}
+
+func TestNewBlockReference(t *testing.T) {
+	ref, err := NewBlockReference("final", nil)
+	require.NoError(t, err)
+	require.Equal(t, Final(), ref)
+
+	ref, err = NewBlockReference("", 10)
+	require.NoError(t, err)
+	require.Equal(t, BlockHeight(10), ref)
+
+	ref, err = NewBlockReference("", "abc")
+	require.NoError(t, err)
+	require.Equal(t, BlockHash("abc"), ref)
+
+	ref, err = NewBlockReference("", nil)
+	require.NoError(t, err)
+	require.True(t, ref.IsZero())
+
+	_, err = NewBlockReference("final", 10)
+	require.Error(t, err)
+	_, err = NewBlockReference("", 1.5)
+	require.Error(t, err)
+}
@@ -1,38 +1,38 @@
 package types
 
 import (
-	"github.com/ethereum/go-ethereum/rpc"
+	"math/big"
+
+	"github.com/textileio/near-api-go/cache"
+	"github.com/textileio/near-api-go/jsonrpc"
 	"github.com/textileio/near-api-go/keys"
+	"github.com/textileio/near-api-go/util"
 )
 
 // Config configures the NEAR client.
 type Config struct {
 	Signer    keys.KeyPair // TODO: model the Signer to wrap KeyPair.
 	NetworkID string
-	RPCClient *rpc.Client
-
-	// /**
-	//  * {@link https://github.com/near/near-contract-helper | NEAR Contract Helper} url used
-	//  * to create accounts if no master account is provided
-	//  * @see {@link UrlAccountCreator}
-	//  */
-	// helperUrl string
-
-	// /**
-	// * The balance transferred from the {@link NearConfig.masterAccount | masterAccount} to a created account
-	// * @see {@link LocalAccountCreator}
-	//  */
-	// initialBalance string
-
-	// /**
-	// * The account to use when creating new accounts
-	// * @see {@link LocalAccountCreator}
-	//  */
-	// masterAccount string
+	RPCClient jsonrpc.Client
+	// ArchivalRPCClient is an optional client for an archival node. Queries referencing a block
+	// that's unknown or garbage collected on the RPCClient node are retried against it.
+	ArchivalRPCClient jsonrpc.Client
+	// RetryPolicy controls how RPC calls failing with transient errors are retried.
+	// Calls aren't retried if nil.
+	RetryPolicy *util.RetryPolicy
+	// RateLimiter limits the rate of RPC calls. Calls aren't limited if nil.
+	RateLimiter *util.RateLimiter
+	// Cache caches responses of queries referencing immutable blocks, and access keys.
+	// Responses aren't cached if nil.
+	Cache *cache.Cache
 
-	// /**
-	// * NEAR wallet url used to redirect users to their wallet in browser applications.
-	// * @see {@link https://docs.near.org/docs/tools/near-wallet}
-	//  */
-	// walletUrl string
+	// HelperURL is the url of the NEAR contract helper, used to create accounts
+	// if no master account is configured.
+	HelperURL string
+	// WalletURL is the url of the NEAR wallet, used to redirect users to their wallet.
+	WalletURL string
+	// MasterAccount is the account used to create accounts.
+	MasterAccount string
+	// InitialBalance is the balance transferred from the master account to created accounts.
+	InitialBalance *big.Int
 }
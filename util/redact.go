@@ -0,0 +1,148 @@
+package util
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces secrets redacted by Redact.
+const Redacted = "[REDACTED]"
+
+// minSecretLen is the length of the shortest secret that's redacted. Shorter values
+// are more likely to match unrelated text than to be credentials.
+const minSecretLen = 4
+
+var secrets = struct {
+	sync.RWMutex
+	values map[string]int
+}{values: make(map[string]int)}
+
+// AddSecret registers a secret, like an API key or a token, to be redacted from errors
+// and logs. Secrets are reference counted, so a secret added twice stays registered
+// until it's removed twice.
+func AddSecret(secret string) {
+	if len(secret) < minSecretLen {
+		return
+	}
+	secrets.Lock()
+	defer secrets.Unlock()
+	secrets.values[secret]++
+}
+
+// RemoveSecret unregisters a secret added with AddSecret.
+func RemoveSecret(secret string) {
+	secrets.Lock()
+	defer secrets.Unlock()
+	if n, ok := secrets.values[secret]; ok {
+		if n <= 1 {
+			delete(secrets.values, secret)
+		} else {
+			secrets.values[secret] = n - 1
+		}
+	}
+}
+
+// credentialParams are the query parameters whose values URLSecrets treats as credentials.
+var credentialParams = map[string]bool{
+	"apikey":       true,
+	"api_key":      true,
+	"key":          true,
+	"token":        true,
+	"access_token": true,
+}
+
+// URLSecrets returns the password and the values of credential query parameters, like apikey
+// or token, of a url. They should be registered with AddSecret.
+func URLSecrets(rawURL string) []string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil
+	}
+	var res []string
+	if password, ok := u.User.Password(); ok {
+		res = append(res, password)
+	}
+	for name, values := range u.Query() {
+		if credentialParams[strings.ToLower(name)] {
+			res = append(res, values...)
+		}
+	}
+	return res
+}
+
+// Redact replaces the registered secrets in s with Redacted.
+func Redact(s string) string {
+	secrets.RLock()
+	values := make([]string, 0, len(secrets.values))
+	for v := range secrets.values {
+		if strings.Contains(s, v) {
+			values = append(values, v)
+		}
+	}
+	secrets.RUnlock()
+	// Replace longer secrets first, in case one secret contains another.
+	sort.Slice(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+	for _, v := range values {
+		s = strings.ReplaceAll(s, v, Redacted)
+	}
+	return s
+}
+
+// RedactedError is an error with registered secrets redacted from its message.
+type RedactedError struct {
+	msg string
+	err error
+}
+
+// Error implements error.
+func (e *RedactedError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the error wrapped by the original error, with registered secrets redacted, so
+// errors.Unwrap and errors.As can't expose them. The original error itself is never returned,
+// so Unwrap returns nil if it doesn't wrap another error.
+func (e *RedactedError) Unwrap() error {
+	return RedactError(errors.Unwrap(e.err))
+}
+
+// RedactError returns an error with the registered secrets redacted from its message and
+// from the errors it wraps. The error is returned as is if neither contain any. A *url.Error
+// is returned as a copy with its URL and wrapped error redacted.
+func RedactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(*url.Error); ok {
+		redacted := &url.Error{Op: e.Op, URL: Redact(e.URL), Err: RedactError(e.Err)}
+		if redacted.URL == e.URL && redacted.Err == e.Err {
+			return err
+		}
+		return redacted
+	}
+	msg := err.Error()
+	redacted := Redact(msg)
+	if redacted == msg && !wrapsSecret(err) {
+		return err
+	}
+	return &RedactedError{msg: redacted, err: err}
+}
+
+// wrapsSecret reports whether an error wrapped by err has a registered secret in its message,
+// or is a *url.Error whose URL has one.
+func wrapsSecret(err error) bool {
+	for next := errors.Unwrap(err); next != nil; next = errors.Unwrap(next) {
+		if e, ok := next.(*url.Error); ok && Redact(e.URL) != e.URL {
+			return true
+		}
+		if msg := next.Error(); Redact(msg) != msg {
+			return true
+		}
+	}
+	return false
+}
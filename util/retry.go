@@ -0,0 +1,132 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultMaxAttempts caps the attempts of a RetryPolicy that sets neither MaxAttempts nor MaxElapsedTime.
+const defaultMaxAttempts = 5
+
+// RetryPolicy controls how failed RPC calls are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one. Zero means no limit if
+	// MaxElapsedTime is set, and 5 attempts otherwise.
+	MaxAttempts int
+	// InitialInterval is the wait before the first retry.
+	InitialInterval time.Duration
+	// MaxInterval caps the wait between attempts. Zero means no cap.
+	MaxInterval time.Duration
+	// Multiplier is applied to the wait after every retry. Values below 1 are treated as 1.
+	Multiplier float64
+	// Jitter randomizes each wait by up to the provided fraction, in [0, 1].
+	Jitter float64
+	// MaxElapsedTime is the time after which no more attempts are started. Zero means no limit.
+	MaxElapsedTime time.Duration
+	// Retryable reports whether an error should be retried. IsRetryableError is used if nil.
+	Retryable func(error) bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy suitable for the public NEAR RPC endpoints.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:     5,
+		InitialInterval: 500 * time.Millisecond,
+		MaxInterval:     10 * time.Second,
+		Multiplier:      2,
+		Jitter:          0.2,
+		MaxElapsedTime:  time.Minute,
+	}
+}
+
+// Do runs the provided function until it succeeds, returns an error that isn't
+// retryable, or the policy is exhausted. It returns the context error promptly
+// if the context is done while waiting.
+func (p *RetryPolicy) Do(ctx context.Context, run func(ctx context.Context) error) error {
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsRetryableError
+	}
+	maxAttempts := p.MaxAttempts
+	if maxAttempts <= 0 && p.MaxElapsedTime <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	start := time.Now()
+	wait := p.InitialInterval
+	for attempt := 1; ; attempt++ {
+		err := run(ctx)
+		if err == nil || !retryable(err) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if maxAttempts > 0 && attempt >= maxAttempts {
+			return fmt.Errorf("giving up after %d attempts: %v", attempt, err)
+		}
+		d := p.jitter(wait)
+		if p.MaxElapsedTime > 0 && time.Since(start)+d > p.MaxElapsedTime {
+			return fmt.Errorf("giving up after %d attempts in %s: %v", attempt, time.Since(start).Round(time.Millisecond), err)
+		}
+		if err := Sleep(ctx, d); err != nil {
+			return err
+		}
+		wait = time.Duration(float64(wait) * multiplier)
+		if p.MaxInterval > 0 && wait > p.MaxInterval {
+			wait = p.MaxInterval
+		}
+	}
+}
+
+func (p *RetryPolicy) jitter(d time.Duration) time.Duration {
+	if p.Jitter <= 0 {
+		return d
+	}
+	delta := p.Jitter * float64(d)
+	return time.Duration(float64(d) - delta + rand.Float64()*2*delta)
+}
+
+// IsRetryableError reports whether the error returned from a RPC call is transient:
+// a NEAR TIMEOUT_ERROR, a HTTP 408, 429 or 5xx response, or a network timeout.
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	msg := err.Error()
+	if strings.Contains(msg, "TIMEOUT_ERROR") {
+		return true
+	}
+	// Non 2xx HTTP responses are returned as errors holding the response status, i.e. "429 Too Many Requests".
+	if len(msg) >= 4 && msg[3] == ' ' {
+		if code, err := strconv.Atoi(msg[:3]); err == nil {
+			return code == 408 || code == 429 || code >= 500
+		}
+	}
+	return false
+}
+
+// Sleep waits for the provided duration, or returns the context error if the context is done first.
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -1,36 +1,98 @@
 package util
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
-
-	"github.com/ethereum/go-ethereum/rpc"
 )
 
+// dataError is a RPC error with data, like jsonrpc.Error.
+type dataError interface {
+	Error() string
+	ErrorData() interface{}
+}
+
+// codeError is a RPC error with a code, like jsonrpc.Error.
+type codeError interface {
+	Error() string
+	ErrorCode() int
+}
+
+// causeError is a structured RPC error with a named cause, like jsonrpc.Error.
+type causeError interface {
+	Error() string
+	CauseName() string
+}
+
 // MapRPCError converts a RPC error with nested informatoin to a error with a useful and complete message.
+// Registered secrets are redacted from the message.
 func MapRPCError(rpcErr error) error {
-	if e, ok := rpcErr.(rpc.DataError); ok {
+	if e, ok := rpcErr.(dataError); ok {
 		bytes, err := json.MarshalIndent(e.ErrorData(), "", "  ")
 		if err != nil {
-			return rpcErr
+			return RedactError(rpcErr)
 		}
-		return fmt.Errorf("%s: %s", e.Error(), string(bytes))
+		return RedactError(fmt.Errorf("%s: %s", e.Error(), string(bytes)))
 	}
-	if e, ok := rpcErr.(rpc.Error); ok {
-		return fmt.Errorf("%s with code: %d", e.Error(), e.ErrorCode())
+	if e, ok := rpcErr.(codeError); ok {
+		return RedactError(fmt.Errorf("%s with code: %d", e.Error(), e.ErrorCode()))
 	}
-	return rpcErr
+	return RedactError(rpcErr)
 }
 
-// Retry will retry running the provided function.
+// RetryError is returned by RetryContext when the provided function doesn't finish.
+type RetryError struct {
+	// Attempts is the number of times the function was run.
+	Attempts int
+	// Err is the error that stopped the retries, or nil if they were exhausted.
+	Err error
+}
+
+// Error implements error.
+func (e *RetryError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("failed after %d attempts: %v", e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("failed to finish after %d attempts", e.Attempts)
+}
+
+// Unwrap returns the error that stopped the retries.
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// Retry will retry running the provided function. Like RetryContext, it returns a *RetryError.
+// Earlier versions returned the error of the function as is, and "failed to finish after N
+// retries" when the retries were exhausted, so callers matching on those messages should use
+// errors.As and errors.Is instead.
 func Retry(numRetries int, retryWait time.Duration, backoff float32, run func(done *bool) error) error {
+	return RetryContext(context.Background(), numRetries, retryWait, backoff, func(_ context.Context, done *bool) error {
+		return run(done)
+	})
+}
+
+// RetryContext will retry running the provided function until it sets done, returns an error,
+// or numRetries is reached. It stops waiting and returns promptly when the context is done.
+// The returned error is a *RetryError holding the number of attempts made.
+func RetryContext(
+	ctx context.Context,
+	numRetries int,
+	retryWait time.Duration,
+	backoff float32,
+	run func(ctx context.Context, done *bool) error,
+) error {
 	wait := retryWait
 	done := false
 	for i := 0; i < numRetries; i++ {
-		err := run(&done)
+		if err := ctx.Err(); err != nil {
+			return &RetryError{Attempts: i, Err: err}
+		}
+		err := run(ctx, &done)
 		if err != nil {
-			return err
+			return &RetryError{Attempts: i + 1, Err: err}
 		}
 		if done {
 			return nil
@@ -40,7 +102,54 @@ func Retry(numRetries int, retryWait time.Duration, backoff float32, run func(do
 			newWaitNanosF := waitNanosF * backoff
 			wait = time.Duration(int64(newWaitNanosF))
 		}
-		time.Sleep(wait)
+		if i < numRetries-1 {
+			if err := Sleep(ctx, wait); err != nil {
+				return &RetryError{Attempts: i + 1, Err: err}
+			}
+		}
+	}
+	return &RetryError{Attempts: numRetries}
+}
+
+// IsUnknownBlockError reports whether the error returned from a RPC call indicates
+// the requested block doesn't exist on the node, either because no block was
+// produced at that height or because it has been garbage collected.
+// Structured errors are matched on their UNKNOWN_BLOCK cause. Other errors, including
+// the ones already mapped with MapRPCError, are matched on block not found messages.
+func IsUnknownBlockError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if name, ok := errorCauseName(err); ok {
+		return name == "UNKNOWN_BLOCK"
+	}
+	msg := MapRPCError(err).Error()
+	return strings.Contains(msg, "UNKNOWN_BLOCK") ||
+		strings.Contains(msg, "DB Not Found Error: BLOCK") ||
+		strings.Contains(msg, "Block either has never been observed on the node")
+}
+
+// IsGarbageCollectedError reports whether the error returned from a RPC call indicates
+// the data of the requested block has been garbage collected on the node, so it's only
+// available from an archival node. Like IsUnknownBlockError, structured errors are matched
+// on their cause.
+func IsGarbageCollectedError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if name, ok := errorCauseName(err); ok {
+		return name == "GARBAGE_COLLECTED_BLOCK"
+	}
+	msg := MapRPCError(err).Error()
+	return strings.Contains(msg, "GARBAGE_COLLECTED_BLOCK") ||
+		strings.Contains(msg, "garbage collected")
+}
+
+// errorCauseName returns the cause name of the structured RPC error in the chain of err.
+func errorCauseName(err error) (string, bool) {
+	var e causeError
+	if errors.As(err, &e) && e.CauseName() != "" {
+		return e.CauseName(), true
 	}
-	return fmt.Errorf("failed to finish after %d retries", numRetries)
+	return "", false
 }
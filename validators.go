@@ -0,0 +1,159 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/textileio/near-api-go/internal/call"
+	itypes "github.com/textileio/near-api-go/internal/types"
+	"github.com/textileio/near-api-go/jsonrpc"
+	"github.com/textileio/near-api-go/types"
+	"github.com/textileio/near-api-go/util"
+)
+
+// CurrentValidator holds information about a validator of the current epoch.
+type CurrentValidator struct {
+	AccountID         string `json:"account_id"`
+	PublicKey         string `json:"public_key"`
+	IsSlashed         bool   `json:"is_slashed"`
+	Stake             string `json:"stake"`
+	Shards            []int  `json:"shards"`
+	NumProducedBlocks int    `json:"num_produced_blocks"`
+	NumExpectedBlocks int    `json:"num_expected_blocks"`
+	NumProducedChunks int    `json:"num_produced_chunks"`
+	NumExpectedChunks int    `json:"num_expected_chunks"`
+}
+
+// NextValidator holds information about a validator of the next epoch.
+type NextValidator struct {
+	AccountID string `json:"account_id"`
+	PublicKey string `json:"public_key"`
+	Stake     string `json:"stake"`
+	Shards    []int  `json:"shards"`
+}
+
+// ValidatorProposal holds information about a staking proposal.
+type ValidatorProposal struct {
+	AccountID string `json:"account_id"`
+	PublicKey string `json:"public_key"`
+	Stake     string `json:"stake"`
+}
+
+// KickoutReason describes why a validator was kicked out.
+type KickoutReason struct {
+	// Type is the reason, such as NotEnoughBlocks, NotEnoughChunks, NotEnoughStake, Slashed,
+	// Unstaked or DidNotGetASeat.
+	Type string
+	// Produced and Expected are set for NotEnoughBlocks and NotEnoughChunks.
+	Produced int
+	Expected int
+	// Stake and Threshold are set for NotEnoughStake.
+	Stake     string
+	Threshold string
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (r *KickoutReason) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		*r = KickoutReason{Type: name}
+		return nil
+	}
+	var m map[string]struct {
+		Produced  int    `json:"produced"`
+		Expected  int    `json:"expected"`
+		Stake     string `json:"stake_u128"`
+		Threshold string `json:"threshold_u128"`
+	}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return fmt.Errorf("unmarshaling kickout reason: %v", err)
+	}
+	if len(m) != 1 {
+		return fmt.Errorf("expected a single kickout reason, got %d", len(m))
+	}
+	for name, details := range m {
+		*r = KickoutReason{
+			Type:      name,
+			Produced:  details.Produced,
+			Expected:  details.Expected,
+			Stake:     details.Stake,
+			Threshold: details.Threshold,
+		}
+	}
+	return nil
+}
+
+// ValidatorKickout holds information about a validator kicked out in the previous epoch.
+type ValidatorKickout struct {
+	AccountID string        `json:"account_id"`
+	Reason    KickoutReason `json:"reason"`
+}
+
+// ValidatorsResponse holds information about the validators of an epoch.
+type ValidatorsResponse struct {
+	CurrentValidators []CurrentValidator  `json:"current_validators"`
+	NextValidators    []NextValidator     `json:"next_validators"`
+	CurrentFishermen  []ValidatorProposal `json:"current_fishermen"`
+	NextFishermen     []ValidatorProposal `json:"next_fishermen"`
+	CurrentProposals  []ValidatorProposal `json:"current_proposals"`
+	PrevEpochKickout  []ValidatorKickout  `json:"prev_epoch_kickout"`
+	EpochStartHeight  int                 `json:"epoch_start_height"`
+	EpochHeight       int                 `json:"epoch_height"`
+}
+
+// ValidatorsOption controls the behavior when calling Validators.
+type ValidatorsOption func(*itypes.ValidatorsRequest)
+
+// ValidatorsWithBlockReference specifies a block in the epoch to query validators for.
+// The block must be referenced by height or hash.
+func ValidatorsWithBlockReference(ref types.BlockReference) ValidatorsOption {
+	return func(vr *itypes.ValidatorsRequest) {
+		vr.Finality = string(ref.Finality())
+		vr.BlockID = ref.BlockID()
+	}
+}
+
+// ValidatorsWithBlockHeight specifies a block height in the epoch to query validators for.
+func ValidatorsWithBlockHeight(blockHeight int) ValidatorsOption {
+	return ValidatorsWithBlockReference(types.BlockHeight(blockHeight))
+}
+
+// ValidatorsWithBlockHash specifies a block hash in the epoch to query validators for.
+func ValidatorsWithBlockHash(blockHash string) ValidatorsOption {
+	return ValidatorsWithBlockReference(types.BlockHash(blockHash))
+}
+
+// ValidatorsWithEpochID specifies the epoch to query validators for.
+func ValidatorsWithEpochID(epochID string) ValidatorsOption {
+	return func(vr *itypes.ValidatorsRequest) {
+		vr.EpochID = epochID
+	}
+}
+
+// Validators queries the validators of an epoch. The latest epoch is used if no option is provided.
+func (c *Client) Validators(ctx context.Context, opts ...ValidatorsOption) (*ValidatorsResponse, error) {
+	req := &itypes.ValidatorsRequest{}
+	for _, opt := range opts {
+		opt(req)
+	}
+	if req.Finality != "" {
+		return nil, fmt.Errorf("validators can't be queried by finality, provide a block height or block hash")
+	}
+	if req.BlockID != nil && req.EpochID != "" {
+		return nil, fmt.Errorf(
+			"you must provide one of ValidatorsWithBlockHeight, ValidatorsWithBlockHash or ValidatorsWithEpochID",
+		)
+	}
+	var res ValidatorsResponse
+	var err error
+	if req.EpochID != "" {
+		err = call.RPC(ctx, c.config, &res, "validators", jsonrpc.NewNamedParams(req))
+	} else {
+		err = call.Block(ctx, c.config, &res, "validators", req.BlockID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("calling validators rpc: %v", util.MapRPCError(err))
+	}
+	return &res, nil
+}